@@ -0,0 +1,113 @@
+package website
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// anchorHeadings gives each h2 and h3 in the article a deterministic
+// id, derived from the heading text as filenameFrom does, and a self
+// link for sharing deep links.
+func anchorHeadings(article *Element) {
+	ids := newIdCache(article)
+	for _, h := range queryHeadings(article, tocHeadings...) {
+		if isAnchored(h) {
+			continue
+		}
+		id := h.AttrVal("id")
+		if id == "" {
+			id = ids.unique(filenameFrom(h.Text()))
+			h.With(Id(id))
+		}
+		h.With(" ", A(Class("self"), Href("#"+id), "&sect;"))
+	}
+}
+
+func isAnchored(h *Element) bool {
+	return len(Query(h, "a.self")) > 0
+}
+
+// headingText returns text of the heading without the self link.
+func headingText(h *Element) string {
+	text := Wrap()
+	for _, c := range h.Children {
+		if e, ok := c.(*Element); ok && e.Name == "a" && e.AttrVal("class") == "self" {
+			continue
+		}
+		text.With(c)
+	}
+	return strings.TrimSpace(text.Text())
+}
+
+// checkLinks returns an error if any link, within or between the
+// given pages, refers to a missing page or id.
+func checkLinks(pages []*Page) error {
+	ids := make(map[string]map[string]int)
+	for _, page := range pages {
+		ids[page.Filename] = newIdCache(page.Element).used
+	}
+	for _, page := range pages {
+		for _, a := range Query(page.Element, "a") {
+			href := a.AttrVal("href")
+			i := strings.Index(href, "#")
+			if i == -1 || isRemote(href) || strings.HasPrefix(href, refScheme) {
+				continue
+			}
+			filename, id := href[:i], href[i+1:]
+			if filename == "" {
+				filename = page.Filename
+			} else {
+				filename = path.Join(path.Dir(page.Filename), filename)
+			}
+			used, found := ids[filename]
+			if !found && !strings.HasSuffix(filename, ".html") {
+				continue // e.g. a zip or image
+			}
+			if _, found := used[id]; !found {
+				return fmt.Errorf("%s: broken link %q", page.Filename, href)
+			}
+		}
+	}
+	return nil
+}
+
+// newIdCache returns a cache aware of all ids already used in root.
+func newIdCache(root *Element) *idCache {
+	c := &idCache{used: make(map[string]int)}
+	WalkElements(root, func(e *Element) {
+		c.add(e.AttrVal("id"), 0)
+	})
+	return c
+}
+
+// idCache keeps track of used ids, e.g. to find duplicates.
+type idCache struct {
+	used map[string]int // id to line of first use, 0 if unknown
+}
+
+// add marks a non empty id as used on the given line. Returns the line
+// of first use and true if already used.
+func (me *idCache) add(id string, line int) (int, bool) {
+	if id == "" {
+		return 0, false
+	}
+	if first, found := me.used[id]; found {
+		return first, true
+	}
+	me.used[id] = line
+	return line, false
+}
+
+// unique returns id, suffixed with a number if already used.
+func (me *idCache) unique(id string) string {
+	res := id
+	for n := 2; ; n++ {
+		if _, dup := me.add(res, 0); !dup {
+			return res
+		}
+		res = fmt.Sprintf("%s_%d", id, n)
+	}
+}
@@ -0,0 +1,42 @@
+package website
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_anchorHeadings(t *testing.T) {
+	article := Article(H1("A"), H2("Setup"), H3("Setup"), H2("Run it", Id("run")))
+	anchorHeadings(article)
+	var ids []string
+	for _, h := range queryHeadings(article, "h2", "h3") {
+		ids = append(ids, h.AttrVal("id"))
+	}
+	assert := asserter.New(t)
+	assert().Equals(ids, []string{"setup", "setup_2", "run"})
+}
+
+func Test_checkLinks(t *testing.T) {
+	a := NewFile("a.html", Article(H1("A"), H2("Package naming")))
+	anchorHeadings(a.Element)
+	b := NewFile("b.html", Article(
+		A(Href("a.html#package_naming")),
+		A(Href("https://example.com/#x")),
+	))
+	ok, bad := asserter.NewErrors(t)
+	ok(checkLinks([]*Page{a, b}))
+
+	// renamed heading
+	b.With(A(Href("a.html#package_names")))
+	bad(checkLinks([]*Page{a, b}))
+
+	// unknown page
+	c := NewFile("c.html", A(Href("renamed.html#package_naming")))
+	bad(checkLinks([]*Page{a, c}))
+
+	// relative to page in directory
+	d := NewFile("drill/d.html", A(Href("../a.html#package_naming")))
+	ok(checkLinks([]*Page{a, d}))
+}
@@ -0,0 +1,270 @@
+package website
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// staticDir holds files published as is, e.g. images in img/,
+// relative to the repository root.
+var staticDir = "docs"
+
+// saveAssets writes minified themes and images of static dir under
+// fingerprinted filenames to base, removing earlier fingerprints of
+// the same files. All references to them in pages and themes
+// are rewritten, so browsers never keep stale copies after publishing.
+func (me *Website) saveAssets(base string) error {
+	themes := make([][]byte, len(me.themes))
+	for i, theme := range me.themes {
+		var buf bytes.Buffer
+		theme.WriteTo(&buf)
+		themes[i] = minifyCSS(buf.Bytes())
+	}
+
+	refs := make([]string, 0)
+	for _, page := range me.pages {
+		for _, img := range Query(page.Element, "img") {
+			refs = append(refs, img.AttrVal("src"))
+		}
+		for _, meta := range Query(page.Element, "meta") {
+			refs = append(refs, me.relative(meta.AttrVal("content")))
+		}
+		for _, style := range Query(page.Element, "style") {
+			refs = append(refs, cssURLs(styleText(style))...)
+		}
+	}
+	for _, data := range themes {
+		refs = append(refs, cssURLs(string(data))...)
+	}
+
+	names := make(map[string]string) // plain to fingerprinted
+	for _, src := range refs {
+		src = strings.TrimLeft(src, "./")
+		if _, done := names[src]; done || !strings.HasPrefix(src, "img/") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(staticDir, src))
+		if err != nil {
+			continue // not one of ours
+		}
+		if err := saveFingerprinted(base, src, data); err != nil {
+			return err
+		}
+		names[src] = fingerprint(src, data)
+	}
+
+	for i, theme := range me.themes {
+		data := rewriteCSS(themes[i], names)
+		if err := saveFingerprinted(base, theme.Filename, data); err != nil {
+			return err
+		}
+		names[theme.Filename] = fingerprint(theme.Filename, data)
+	}
+
+	absolute := make(map[string]string)
+	for plain, name := range names {
+		if v := me.absolute(plain); v != "" {
+			absolute[v] = me.absolute(name)
+		}
+	}
+	for _, page := range me.pages {
+		rewrite(Query(page.Element, "link"), "href", names)
+		rewrite(Query(page.Element, "img"), "src", names)
+		rewriteMeta(Query(page.Element, "meta"), absolute)
+		for _, script := range Query(page.Element, "script") {
+			if script.AttrVal("type") == "application/ld+json" {
+				rewriteJSON(script, absolute)
+			}
+		}
+		for _, style := range Query(page.Element, "style") {
+			text := styleText(style)
+			if v := rewriteCSS([]byte(text), names); string(v) != text {
+				style.Children = []interface{}{string(v)}
+			}
+		}
+	}
+	return nil
+}
+
+// saveFingerprinted writes data to base under the fingerprinted
+// filename and removes other fingerprints of the same file.
+func saveFingerprinted(base, filename string, data []byte) error {
+	name := fingerprint(filename, data)
+	dir := filepath.Join(base, path.Dir(filename))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ext := path.Ext(filename)
+	stem := path.Base(strings.TrimSuffix(filename, ext))
+	stale := regexp.MustCompile(
+		`^` + regexp.QuoteMeta(stem) + `\.[0-9a-f]{8}` + regexp.QuoteMeta(ext) + `$`,
+	)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() != path.Base(name) && stale.MatchString(e.Name()) {
+			if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return os.WriteFile(filepath.Join(base, name), data, 0644)
+}
+
+// relative returns href without the base url, empty if not below it.
+func (me *Website) relative(href string) string {
+	if me.baseURL == "" {
+		return ""
+	}
+	v, found := strings.CutPrefix(href, me.baseURL+"/")
+	if !found {
+		return ""
+	}
+	return v
+}
+
+// rewrite replaces attribute values found in names, keeping any
+// ../ prefix of pages in subdirectories.
+func rewrite(elements []*Element, attr string, names map[string]string) {
+	for _, e := range elements {
+		a := e.Attr(attr)
+		if a == nil {
+			continue
+		}
+		up := ""
+		v := a.Val
+		for strings.HasPrefix(v, "../") {
+			up += "../"
+			v = v[3:]
+		}
+		if name, found := names[v]; found {
+			a.Val = up + name
+		}
+	}
+}
+
+// rewriteMeta replaces absolute urls in content of meta elements,
+// e.g. og:image.
+func rewriteMeta(elements []*Element, absolute map[string]string) {
+	for _, e := range elements {
+		if a := e.Attr("content"); a != nil {
+			if v, found := absolute[a.Val]; found {
+				a.Val = v
+			}
+		}
+	}
+}
+
+// rewriteJSON replaces absolute urls in JSON-LD of the script element.
+func rewriteJSON(script *Element, absolute map[string]string) {
+	for i, c := range script.Children {
+		v, ok := c.(string)
+		if !ok {
+			continue
+		}
+		for from, to := range absolute {
+			v = strings.ReplaceAll(v, `"`+from+`"`, `"`+to+`"`)
+		}
+		script.Children[i] = v
+	}
+}
+
+var cssURL = regexp.MustCompile(`url\(\s*['"]?([^'")\s]+)['"]?\s*\)`)
+
+// cssURLs returns all url(...) references in css.
+func cssURLs(css string) []string {
+	res := make([]string, 0)
+	for _, m := range cssURL.FindAllStringSubmatch(css, -1) {
+		res = append(res, m[1])
+	}
+	return res
+}
+
+// rewriteCSS replaces url(...) references found in names.
+func rewriteCSS(css []byte, names map[string]string) []byte {
+	return cssURL.ReplaceAllFunc(css, func(m []byte) []byte {
+		src := string(cssURL.FindSubmatch(m)[1])
+		if name, found := names[src]; found {
+			return bytes.Replace(m, []byte(src), []byte(name), 1)
+		}
+		return m
+	})
+}
+
+// styleText returns the css of a style element.
+func styleText(style *Element) string {
+	var buf bytes.Buffer
+	for _, c := range style.Children {
+		if css, ok := c.(*CSS); ok {
+			css.WriteTo(&buf)
+			continue
+		}
+		fmt.Fprint(&buf, c)
+	}
+	return buf.String()
+}
+
+// fingerprint returns filename with a hash of data before the
+// extension, e.g. theme.1a2b3c4d.css
+func fingerprint(filename string, data []byte) string {
+	sum := sha256.Sum256(data)
+	ext := path.Ext(filename)
+	return fmt.Sprintf("%s.%x%s", strings.TrimSuffix(filename, ext), sum[:4], ext)
+}
+
+var cssSpace = regexp.MustCompile(`\s+`)
+
+// minifyCSS removes whitespace and the last semicolon of each rule
+// set. Spaces around colons are only removed in declarations and
+// at-rule preludes, e.g. (max-width: 22cm), as they are descendant
+// combinators in selectors. Quoted strings must not contain
+// delimiters, which holds for web.CSS themes of this website.
+func minifyCSS(v []byte) []byte {
+	v = cssSpace.ReplaceAll(bytes.TrimSpace(v), []byte(" "))
+	var (
+		res   = make([]byte, 0, len(v))
+		decl  []bool // stack of blocks, true for declaration blocks
+		start int    // of current prelude or declaration in res
+	)
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == ':' && !(len(decl) > 0 && decl[len(decl)-1]) &&
+			!bytes.HasPrefix(res[start:], []byte("@")):
+			res = append(res, c) // pseudo-class in selector
+
+		case bytes.IndexByte([]byte("{};:,"), c) >= 0:
+			res = bytes.TrimSuffix(res, []byte(" "))
+			switch c {
+			case '{':
+				decl = append(decl, !bytes.HasPrefix(res[start:], []byte("@")))
+			case '}':
+				res = bytes.TrimSuffix(res, []byte(";"))
+				if len(decl) > 0 {
+					decl = decl[:len(decl)-1]
+				}
+			}
+			res = append(res, c)
+			if i+1 < len(v) && v[i+1] == ' ' {
+				i++
+			}
+			if c == '{' || c == '}' || c == ';' {
+				start = len(res)
+			}
+
+		default:
+			res = append(res, c)
+		}
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package website
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_minifyCSS(t *testing.T) {
+	got := string(minifyCSS([]byte(`
+@media screen and (max-width: 22cm){
+h1, h2 {
+font-family: 'Source Sans Pro', sans-serif;
+margin: 0 0;
+}
+}
+`)))
+	assert := asserter.New(t)
+	assert().Equals(got,
+		"@media screen and (max-width:22cm){h1,h2{font-family:'Source Sans Pro',sans-serif;margin:0 0}}",
+	)
+
+	got = string(minifyCSS([]byte(`a :hover { color : red; }
+@media print { nav :first-child, a:visited { margin : 0 } }`)))
+	assert().Equals(got,
+		"a :hover{color:red}@media print{nav :first-child,a:visited{margin:0}}",
+	)
+}
+
+func Test_fingerprint(t *testing.T) {
+	assert := asserter.New(t)
+	a := fingerprint("img/a.png", []byte("a"))
+	assert().Equals(a, "img/a.ca978112.png")
+	assert(a != fingerprint("img/a.png", []byte("b"))).Error(
+		"same fingerprint for different content",
+	)
+}
+
+func Test_rewrite(t *testing.T) {
+	links := []*Element{
+		stylesheet("a4.css"),
+		stylesheet("../a4.css"),
+		stylesheet("other.css"),
+	}
+	rewrite(links, "href", map[string]string{"a4.css": "a4.1234.css"})
+	var got []string
+	for _, link := range links {
+		got = append(got, link.AttrVal("href"))
+	}
+	assert := asserter.New(t)
+	assert().Equals(got, []string{"a4.1234.css", "../a4.1234.css", "other.css"})
+}
+
+func Test_saveAssets(t *testing.T) {
+	static := t.TempDir()
+	defer func(v string) { staticDir = v }(staticDir)
+	staticDir = static
+	os.MkdirAll(filepath.Join(static, "img"), 0755)
+	os.WriteFile(filepath.Join(static, "img/a.png"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(static, "img/bg.png"), []byte("bg"), 0644)
+
+	css := NewCSS()
+	css.Filename = "theme.css"
+	css.Style("body", "background: url(img/bg.png)")
+	site := &Website{baseURL: "https://x.example", themes: []*CSS{css}}
+	article := Article(H1("A"), Img(Src("img/a.png")))
+	site.add(NewFile("a.html", Html(Head(
+		stylesheet("theme.css"),
+		site.metadata("Article", "A", "a.html", article),
+	), Body(article))))
+
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "img"), 0755)
+	os.WriteFile(filepath.Join(base, "img/a.00000000.png"), nil, 0644)
+	os.WriteFile(filepath.Join(base, "img/ab.00000000.png"), nil, 0644)
+	ok, bad := asserter.NewErrors(t)
+	ok(site.saveAssets(base))
+
+	assert := asserter.New(t)
+	png := fingerprint("img/a.png", []byte("a"))
+	page := site.pages[0].Element
+	assert().Equals(MustQueryOne(page, "img").AttrVal("src"), png)
+	assert().Equals(meta(page, "property", "og:image"), "https://x.example/"+png)
+	var ld struct{ Image string }
+	ok(json.Unmarshal([]byte(MustQueryOne(page, "script").Text()), &ld))
+	assert().Equals(ld.Image, "https://x.example/"+png)
+	data, err := os.ReadFile(filepath.Join(base, png))
+	ok(err)
+	assert().Equals(string(data), "a")
+
+	theme, _ := filepath.Glob(filepath.Join(base, "theme.*.css"))
+	assert(len(theme) == 1).Fatal("missing theme", theme)
+	assert().Equals(stylesheets(site.pages[0]), []string{filepath.Base(theme[0])})
+	data, _ = os.ReadFile(theme[0])
+	bg := fingerprint("img/bg.png", []byte("bg"))
+	assert().Equals(string(data), "body{background:url("+bg+")}")
+
+	_, err = os.Stat(filepath.Join(base, "img/a.00000000.png"))
+	bad(err) // stale fingerprint removed
+	_, err = os.Stat(filepath.Join(base, "img/ab.00000000.png"))
+	ok(err) // of other file kept
+}
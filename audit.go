@@ -0,0 +1,252 @@
+package website
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
+)
+
+// Severity of an accessibility finding.
+type Severity int
+
+const (
+	Notice Severity = iota + 1
+	Warning
+	Failure
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Notice:
+		return "notice"
+	case Warning:
+		return "warning"
+	case Failure:
+		return "failure"
+	}
+	return "off"
+}
+
+// ParseSeverity returns the severity named by v, e.g. "warning". An
+// empty v or "off" returns zero.
+func ParseSeverity(v string) (Severity, error) {
+	for s := Severity(0); s <= Failure; s++ {
+		if s.String() == v {
+			return s, nil
+		}
+	}
+	if v == "" {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q", v)
+}
+
+// WithAudit fails saving the website if the accessibility audit finds
+// problems of the given severity or worse, see Website.Audit.
+func WithAudit(level Severity) SiteOption {
+	return func(w *Website) {
+		w.auditLevel = level
+	}
+}
+
+// Finding is a problem found by the accessibility audit.
+type Finding struct {
+	Severity
+	Filename string
+	Msg      string
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Filename, f.Severity, f.Msg)
+}
+
+// Audit returns accessibility problems of all pages and the color
+// palettes used by themes.
+func (me *Website) Audit() []*Finding {
+	res := make([]*Finding, 0)
+	for _, page := range me.pages {
+		res = append(res, auditPage(page)...)
+	}
+	t := tokens.Default
+	res = append(res, auditContrast("light palette", t.Light)...)
+	res = append(res, auditContrast("dark palette", t.Dark)...)
+	return res
+}
+
+// checkAudit returns an error listing findings of level or worse.
+func checkAudit(findings []*Finding, level Severity) error {
+	if level == 0 {
+		return nil
+	}
+	failed := make([]string, 0)
+	for _, f := range findings {
+		if f.Severity >= level {
+			failed = append(failed, f.String())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("accessibility audit:\n%s", strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+func auditPage(page *Page) []*Finding {
+	res := make([]*Finding, 0)
+	add := func(s Severity, format string, args ...interface{}) {
+		res = append(res, &Finding{
+			Severity: s,
+			Filename: page.Filename,
+			Msg:      fmt.Sprintf(format, args...),
+		})
+	}
+	ids := &idCache{used: make(map[string]int)}
+	var level int // of last heading
+	WalkElements(page.Element, func(e *Element) {
+		if _, dup := ids.add(e.AttrVal("id"), 0); dup {
+			add(Failure, "duplicate id %q", e.AttrVal("id"))
+		}
+		switch e.Name {
+		case "img":
+			if e.Attr("alt") == nil {
+				add(Failure, "img %q without alt text", e.AttrVal("src"))
+			}
+		case "a":
+			// references get their text when resolved, see resolveRefs
+			if strings.HasPrefix(e.AttrVal("href"), refScheme) {
+				break
+			}
+			if accessibleName(e) == "" {
+				add(Failure, "link %q without text", e.AttrVal("href"))
+			}
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			n := int(e.Name[1] - '0')
+			if n > level+1 {
+				add(Warning, "heading %s %q skips level h%v",
+					e.Name, plainText(headingText(e)), level+1,
+				)
+			}
+			level = n
+		}
+	})
+	return res
+}
+
+// accessibleName returns text read by screen readers for e.
+func accessibleName(e *Element) string {
+	if v := e.AttrVal("aria-label"); v != "" {
+		return v
+	}
+	if v := e.AttrVal("title"); v != "" {
+		return v
+	}
+	var name string
+	WalkElements(e, func(c *Element) {
+		if c.Name == "img" {
+			name += c.AttrVal("alt")
+		}
+	})
+	return strings.TrimSpace(name + plainText(e.Text()))
+}
+
+// auditContrast checks contrast of foreground and background pairs in
+// the palette against WCAG levels, 4.5:1 for text and 3:1 for large or
+// decorative text.
+func auditContrast(name string, p tokens.Palette) []*Finding {
+	pairs := []struct {
+		fg, bg, desc string
+		minor        bool // decorative, e.g. self links shown on hover
+	}{
+		{p.Text, p.Background, "text", false},
+		{p.Link, p.Background, "links", false},
+		{p.Text, p.Code, "code", false},
+		{p.OnAccent, p.Highlight, "unreleased version", false},
+		{p.OnAccent, p.Success, "verified", false},
+		{p.OnAccent, p.Failure, "failing", false},
+		{p.OnAccent, p.Border, "uncovered", false},
+		{p.Muted, p.Background, "self links", true},
+	}
+	res := make([]*Finding, 0)
+	for _, pair := range pairs {
+		ratio, err := contrast(pair.fg, pair.bg)
+		if err != nil {
+			res = append(res, &Finding{Notice, name, err.Error()})
+			continue
+		}
+		var s Severity
+		switch {
+		case pair.minor && ratio < 3:
+			s = Notice
+		case pair.minor:
+			continue
+		case ratio < 3:
+			s = Failure
+		case ratio < 4.5:
+			s = Warning
+		default:
+			continue
+		}
+		res = append(res, &Finding{
+			Severity: s,
+			Filename: name,
+			Msg: fmt.Sprintf("low contrast %.1f:1 of %s, %s on %s",
+				ratio, pair.desc, pair.fg, pair.bg,
+			),
+		})
+	}
+	return res
+}
+
+// contrast returns the WCAG contrast ratio of two colors.
+func contrast(a, b string) (float64, error) {
+	la, err := luminance(a)
+	if err != nil {
+		return 0, err
+	}
+	lb, err := luminance(b)
+	if err != nil {
+		return 0, err
+	}
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05), nil
+}
+
+// namedColors used in themes
+var namedColors = map[string]string{
+	"black": "#000000",
+	"white": "#ffffff",
+	"red":   "#ff0000",
+	"green": "#008000",
+	"blue":  "#0000ff",
+}
+
+// luminance returns the relative luminance of a #rrggbb or named
+// color.
+func luminance(color string) (float64, error) {
+	if v, found := namedColors[color]; found {
+		color = v
+	}
+	if len(color) != 7 || color[0] != '#' {
+		return 0, fmt.Errorf("cannot parse color %q", color)
+	}
+	var l float64
+	for i, weight := range []float64{0.2126, 0.7152, 0.0722} {
+		v, err := strconv.ParseUint(color[1+2*i:3+2*i], 16, 8)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse color %q", color)
+		}
+		c := float64(v) / 255
+		if c <= 0.03928 {
+			c = c / 12.92
+		} else {
+			c = math.Pow((c+0.055)/1.055, 2.4)
+		}
+		l += weight * c
+	}
+	return l, nil
+}
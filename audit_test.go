@@ -0,0 +1,61 @@
+package website
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
+)
+
+func Test_auditPage(t *testing.T) {
+	page := NewFile("a.html", Html(Body(
+		H1("Title"),
+		H3("Skipped"),
+		Img(Src("img/a.png")),
+		Img(Src("img/b.png"), Alt("b")),
+		A(Href("#x")),
+		A(Href("#y"), Img(Src("img/c.png"), Alt("c"))),
+		P(Id("x")),
+		P(Id("x")),
+	)))
+	var got []string
+	for _, f := range auditPage(page) {
+		got = append(got, f.String())
+	}
+	exp := []string{
+		`a.html: warning: heading h3 "Skipped" skips level h2`,
+		`a.html: failure: img "img/a.png" without alt text`,
+		`a.html: failure: link "#x" without text`,
+		`a.html: failure: duplicate id "x"`,
+	}
+	assert := asserter.New(t)
+	assert().Equals(strings.Join(got, "\n"), strings.Join(exp, "\n"))
+	_, bad := asserter.NewErrors(t)
+	bad(checkAudit(auditPage(page), Failure))
+}
+
+func Test_contrast(t *testing.T) {
+	ratio, err := contrast("black", "#ffffff")
+	ok, bad := asserter.NewErrors(t)
+	ok(err)
+	assert := asserter.New(t)
+	assert(ratio == 21).Errorf("ratio %v", ratio)
+	_, err = contrast("chartreuse", "#ffffff")
+	bad(err) // unknown color
+}
+
+func Test_palettes_pass_audit(t *testing.T) {
+	ok, _ := asserter.NewErrors(t)
+	for _, p := range []tokens.Palette{tokens.Light, tokens.Dark} {
+		ok(checkAudit(auditContrast("palette", p), Notice))
+	}
+}
+
+func TestWebsite_Audit_unresolvedRefs(t *testing.T) {
+	site := &Website{}
+	site.add(NewFile("a.html", Html(Body(H1("A"), P(ref("chart"))))))
+	assert := asserter.New(t)
+	assert().Equals(len(site.Audit()), 0)
+}
@@ -0,0 +1,254 @@
+package website
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/gregoryv/web"
+)
+
+// newDrillBundle returns a starter bundle in dir with the drill
+// rewritten to package main.
+func newDrillBundle(dir, args, filename string) *bundle {
+	name := drillName(filename)
+	b := newBundle(path.Join(dir, name+".zip"))
+	b.load = func(b *bundle) error {
+		data, err := readDrillMain(filename)
+		if err != nil {
+			return err
+		}
+		b.Add("go.mod", goMod(name, data))
+		b.Add(filepath.Base(filename), data)
+		b.Add("README.md", readme(drillTitle(filename), args))
+		return nil
+	}
+	return b
+}
+
+// drillMain returns the full drill rewritten to package main. Panics
+// if the drill cannot be read.
+func drillMain(filename string) []byte {
+	data, err := readDrillMain(filename)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func readDrillMain(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return asMain([]byte(stripExerciseMarks(string(data)))), nil
+}
+
+// newExampleBundle returns a starter bundle with all go files found in
+// the given example directory.
+func newExampleBundle(dir string) *bundle {
+	b := newBundle(dir + ".zip")
+	b.load = func(b *bundle) error {
+		filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return err
+		}
+		if len(filenames) == 0 {
+			return fmt.Errorf("no go files in %s", dir)
+		}
+		name := filepath.Base(dir)
+		srcs := make([][]byte, 0, len(filenames))
+		for _, filename := range filenames {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+			srcs = append(srcs, data)
+			b.Add(filepath.Base(filename), data)
+		}
+		b.Add("go.mod", goMod(name, srcs...))
+		b.Add("README.md", readme(name, ""))
+		return nil
+	}
+	return b
+}
+
+// bundleLink returns a link to the starter bundle of the given example
+// directory. The bundle is created when a page with the link is added
+// to the website.
+func bundleLink(dir string) *Element {
+	return A(Class("bundle"), Href(dir+".zip"),
+		"Download ", path.Base(dir), " starter bundle",
+	)
+}
+
+// newBundle returns an empty bundle saved as filename, which is
+// relative to the website base directory. Entries are placed in a
+// directory named as the filename without extension.
+func newBundle(filename string) *bundle {
+	return &bundle{
+		filename: filename,
+		entries:  make(map[string][]byte),
+	}
+}
+
+type bundle struct {
+	filename string
+	entries  map[string][]byte
+
+	// load adds entries from files when the bundle is saved
+	load func(*bundle) error
+}
+
+// Add adds an entry to the bundle, replacing any existing with the
+// same name.
+func (me *bundle) Add(name string, data []byte) {
+	me.entries[name] = data
+}
+
+// Link returns a link to the bundle relative to the given page
+// directory.
+func (me *bundle) Link(pageDir string) *Element {
+	href, _ := filepath.Rel(pageDir, me.filename)
+	return A(Href(filepath.ToSlash(href)), "Download starter bundle")
+}
+
+// SaveTo writes the bundle as a zip archive to the given directory.
+func (me *bundle) SaveTo(dir string) error {
+	if me.load != nil {
+		if err := me.load(me); err != nil {
+			return fmt.Errorf("bundle %s: %w", me.filename, err)
+		}
+	}
+	filename := filepath.Join(dir, me.filename)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := me.writeZip(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// bundleTime is used as modification time of all bundle entries so
+// that archives are reproducible byte-for-byte.
+var bundleTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (me *bundle) writeZip(w *bytes.Buffer) error {
+	names := make([]string, 0, len(me.entries))
+	for name := range me.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	top := strings.TrimSuffix(path.Base(me.filename), path.Ext(me.filename))
+	z := zip.NewWriter(w)
+	for _, name := range names {
+		h := &zip.FileHeader{
+			Name:     path.Join(top, name),
+			Method:   zip.Deflate,
+			Modified: bundleTime,
+		}
+		h.SetMode(0644)
+		fw, err := z.CreateHeader(h)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(me.entries[name]); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// goMod returns a go.mod for the named module requiring all non
+// standard imports found in srcs, using the same versions as this
+// website.
+func goMod(module string, srcs ...[]byte) []byte {
+	versions := requirements("go.mod")
+	required := make(map[string]string)
+	for _, src := range srcs {
+		f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range f.Imports {
+			v, _ := strconv.Unquote(imp.Path.Value)
+			for mod, version := range versions {
+				if v == mod || strings.HasPrefix(v, mod+"/") {
+					required[mod] = version
+				}
+			}
+		}
+	}
+	mods := make([]string, 0, len(required))
+	for mod := range required {
+		mods = append(mods, mod)
+	}
+	sort.Strings(mods)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "module %s\n\ngo 1.21\n", module)
+	if len(mods) > 0 {
+		buf.WriteString("\nrequire (\n")
+		for _, mod := range mods {
+			fmt.Fprintf(&buf, "\t%s %s\n", mod, required[mod])
+		}
+		buf.WriteString(")\n")
+	}
+	return buf.Bytes()
+}
+
+// requirements returns module path to version of all required modules
+// in the given go.mod file.
+func requirements(filename string) map[string]string {
+	res := make(map[string]string)
+	fh, err := os.Open(filename)
+	if err != nil {
+		return res
+	}
+	defer fh.Close()
+	s := bufio.NewScanner(fh)
+	var inside bool
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "require (":
+			inside = true
+			continue
+		case line == ")":
+			inside = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inside:
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			res[fields[0]] = fields[1]
+		}
+	}
+	return res
+}
+
+// readme returns a README.md describing how to run a bundle.
+func readme(title, args string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n", stripTags(title))
+	buf.WriteString("Starter bundle from www.sogvin.com\n\n")
+	buf.WriteString("    $ go mod tidy\n")
+	fmt.Fprintf(&buf, "    $ go run . %s\n", args)
+	return bytes.ReplaceAll(buf.Bytes(), []byte(" \n"), []byte("\n"))
+}
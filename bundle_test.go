@@ -0,0 +1,66 @@
+package website
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_bundle_reproducible(t *testing.T) {
+	b := newDrillBundle("drill", "", "drill/flag_types.go")
+	saved := make([][]byte, 2)
+	for i := range saved {
+		dir := t.TempDir()
+		if err := b.SaveTo(dir); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, b.filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved[i] = data
+	}
+	if !bytes.Equal(saved[0], saved[1]) {
+		t.Error("archives differ")
+	}
+
+	// a new bundle of the same drill must also be equal
+	dir := t.TempDir()
+	other := newDrillBundle("drill", "", "drill/flag_types.go")
+	if err := other.SaveTo(dir); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, other.filename))
+	if !bytes.Equal(saved[0], data) {
+		t.Error("archives of new bundle differ")
+	}
+}
+
+func Test_bundle_errors(t *testing.T) {
+	for _, b := range []*bundle{
+		newDrillBundle("drill", "", "drill/no_such_drill.go"),
+		newExampleBundle("example/no_such_example"),
+		(&exercise{filename: "drill/no_such_drill.go"}).Bundle("drill"),
+	} {
+		if err := b.SaveTo(t.TempDir()); err == nil {
+			t.Errorf("%s: expected error", b.filename)
+		}
+	}
+}
+
+func Test_gettingStarted_bundles(t *testing.T) {
+	var got []string
+	for _, a := range Query(gettingStartedWithProgramming(), "a.bundle") {
+		got = append(got, a.AttrVal("href"))
+	}
+	exp := []string{
+		"example/no1.zip", "example/no2.zip", "example/no3.zip", "example/no4.zip",
+	}
+	assert := asserter.New(t)
+	assert().Equals(strings.Join(got, " "), strings.Join(exp, " "))
+}
@@ -18,7 +18,7 @@ case $1 in
 	go build ./...
 	
 	mkdir -p $dist
-	go run ./cmd/mksite -p $dist/docs
+	go run ./cmd/mksite -p $dist/docs --audit warning
 	# update static files
 	rsync -aC ./docs $dist/
 	echo "dist: $dist"
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"path/filepath"
+	"runtime"
+
+	"github.com/gregoryv/cmdline"
+	"github.com/sogvin/website"
+)
+
+func main() {
+	var (
+		cli       = cmdline.NewBasicParser()
+		bind      = cli.Option("-b, --bind").String(":8080")
+		pattern   = cli.Option("-d, --drills").String("drill/*.go")
+		cpuTime   = cli.Option("--cpu-time").Duration("2s")
+		maxOutput = cli.Option("--max-output").Int(64 << 10)
+		maxBody   = cli.Option("--max-request", "bytes of request body").Int(64 << 10)
+		maxRuns   = cli.Option("--max-runs", "concurrent runs").Int(runtime.NumCPU())
+		isolate   = cli.Flag("--isolate")
+	)
+	cli.Parse()
+
+	log.SetFlags(0)
+
+	filenames, err := filepath.Glob(pattern)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runner := website.NewDrillRunner(filenames...)
+	runner.CPUTime = cpuTime
+	runner.MaxOutput = maxOutput
+	runner.MaxRequest = int64(maxBody)
+	runner.MaxRuns = maxRuns
+	runner.Isolate = isolate
+	if err := runner.Build(); err != nil {
+		log.Fatal(err)
+	}
+	defer runner.Close()
+
+	http.Handle("/run", runner)
+	log.Println("listening on", bind)
+	log.Fatal(http.ListenAndServe(bind, nil))
+}
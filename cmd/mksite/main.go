@@ -14,6 +14,14 @@ func main() {
 	var (
 		cli          = cmdline.NewBasicParser()
 		prefix       = cli.Option("-p, --prefix", "write pages to").String("./docs")
+		playground   = cli.Option("--playground", "base url of self hosted playground importing run links, empty for none").String("")
+		baseURL      = cli.Option("--base-url", "where the website is published").String("https://www.sogvin.com")
+		drillRunner  = cli.Option("--drillrun", "url of drillrun service").String("")
+		tocThreshold = cli.Option("--toc", "headings needed for a table of contents, 0 disables").Int(4)
+		listFigures  = cli.Flag("--list-figures")
+		offline      = cli.Flag("--offline")
+		audit        = cli.Option("--audit", "fail on accessibility findings of notice, warning or failure").String("")
+		fontDir      = cli.Option("--fonts", "bundle font files from, implies --offline").String("")
 		showVersion  = cli.Flag("-v, --version")
 		checkRelease = cli.Flag("-c, --check-release")
 	)
@@ -31,8 +39,29 @@ func main() {
 		}
 
 	default:
+		level, err := website.ParseSeverity(audit)
+		if err != nil {
+			log.Fatal(err)
+		}
 		os.MkdirAll(prefix, 0722)
-		website := website.NewWebsite()
+		options := []website.SiteOption{
+			website.WithPlayground(playground),
+			website.WithTOC(tocThreshold),
+			website.WithBaseURL(baseURL),
+		}
+		if listFigures {
+			options = append(options, website.WithListOfFigures())
+		}
+		if offline || fontDir != "" {
+			options = append(options, website.WithFonts(fontDir))
+		}
+		if level > 0 {
+			options = append(options, website.WithAudit(level))
+		}
+		if drillRunner != "" {
+			options = append(options, website.WithDrillRunner(drillRunner))
+		}
+		website := website.NewWebsite(options...)
 		if err := website.SaveTo(prefix); err != nil {
 			log.Fatal(err)
 		}
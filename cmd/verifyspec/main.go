@@ -0,0 +1,42 @@
+// Command verifyspec renders the specification with verification
+// status of each requirement. Tests declare the requirements they
+// verify with spectest.Verifies or a doc comment line
+//
+//	// verifies: R1 R2
+//
+// Usage:
+//
+//	go test -json ./... | go run ./cmd/verifyspec
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/gregoryv/cmdline"
+	"github.com/sogvin/website/spec"
+)
+
+func main() {
+	var (
+		cli     = cmdline.NewBasicParser()
+		sources = cli.Option("-s, --sources", "scan test files below").String(".")
+		output  = cli.Option("-o, --output").String("spec/docs/index.html")
+	)
+	cli.Parse()
+
+	log.SetFlags(0)
+
+	v := spec.NewVerification()
+	if err := v.ScanSources(sources); err != nil {
+		log.Fatal(err)
+	}
+	if err := v.ReadTestJSON(os.Stdin); err != nil {
+		log.Fatal(err)
+	}
+	page := spec.NewSpecification()
+	v.Mark(page.Element)
+	if err := page.SaveAs(output); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	_ "embed"
 
 	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
 )
 
 func projectLayout() *Element {
@@ -24,7 +25,7 @@ func projectLayout() *Element {
 		sidenote(`Layout should minimize refacto- ring impact.`, -1.8),
 
 		P(`Here is an example of a project layout from `,
-			linkTo(roleBasedService()), "."),
+			ref("system-design-layers-and-access-roles"), "."),
 
 		shellCommand(`$ tree ../navstar/
 ├── cmd
@@ -134,6 +135,10 @@ func projectLayout() *Element {
 		renaming them later is harder, unless you have really
 		sophisticated refactoring tools.`),
 
+		P(`Read more about choosing package names in `,
+			ref("system-design-layers-and-access-roles/package-naming"),
+			"."),
+
 		P(`Once it's time to build an application for others to use,
 		it's benefitial to place this in a directory named the same as
 		the Go build tools default to naming the binary to it. Create
@@ -285,6 +290,8 @@ func purposeOfFuncMain() *Element {
 		increase.`),
 
 		loadFile("./example/cmd/starcounter/starcounter_test.go"),
+
+		P(bundleLink("example/cmd/starcounter")),
 	)
 }
 
@@ -343,7 +350,9 @@ func gracefulServerShutdown() *Element {
 	)
 }
 
-func componentsDiagram() *Element {
+// componentsDiagram describes components diagrams, with the color
+// scheme of the given palette.
+func componentsDiagram(p tokens.Palette) *Element {
 	return Article(
 		H1("Components diagram"),
 		P(
@@ -351,8 +360,8 @@ func componentsDiagram() *Element {
 		to one another. Good for system overviews and microservice
 		architectures.`,
 		),
-		Span(Class("Center"),
-			newOverviewDiagram(),
+		figure("overview", "Service overview with external components dimmed",
+			svg(newOverviewDiagram()),
 		),
 		P(
 			`Use lines between components unless you are conveying
@@ -376,7 +385,37 @@ func componentsDiagram() *Element {
 			),
 			Li("Stick to one color scheme"),
 		),
-		colorSchemeDiagram(),
+		figure("color-scheme", "Color scheme",
+			svg(colorSchemeDiagram(p)),
+		),
+
+		H2("Diagrams from source"),
+		P(
+			`Diagrams of code drift from the code they describe unless
+		they are generated from it. Below class diagram of the
+		visitor pattern example is drawn from the sources in
+		example/visitor each time the site is built.`,
+		),
+		figure("visitor-classes", "Types and interfaces of package visitor",
+			svg(classDiagram("./example/visitor")),
+		),
+		P(
+			`In the same way package dependencies are found from
+		import declarations.`,
+		),
+		figure("behavior-packages", "Packages of the behavior example",
+			svg(packageDiagram("./example/behavior/...")),
+		),
+
+		P(
+			`Sequence diagrams can be recorded by a test calling
+		instrumented components. The test wraps the visited shape and
+		its behaviors, records each call and compares the sequence
+		with a saved one, updated with go test -update.`,
+		),
+		figure("visitor-sequence", "Calculating the area of a circle",
+			svg(loadDiagram("./example/visitor/testdata/calc_area.diagram")),
+		),
 	)
 }
 
@@ -1,73 +1,166 @@
 package website
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
 
 	"github.com/gregoryv/draw"
 	"github.com/gregoryv/draw/design"
 	"github.com/gregoryv/draw/shape"
 	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/diagram"
+	"github.com/sogvin/website/tokens"
 )
 
-func newOverviewDiagram() *Element {
-	var (
-		d        = design.NewClassDiagram()
-		serviced = shape.NewComponent("serviced")
-		ng       = shape.NewComponent("nginx")
-		inet     = shape.NewCircle(40)
-		client   = shape.NewComponent("client")
-		db       = shape.NewDatabase("postgres")
-		cloud    = shape.NewLabel("internet")
-	)
-	shape.SetClass("external", ng, client, inet, db)
+func newOverviewDiagram() diagram.Diagram {
+	return parseDiagram(`
+component serviced
+database db postgres
+component ng nginx
+circle inet 40
+label cloud internet
+component client
+class external ng client inet db
 
-	d.Place(serviced).At(130, 20)
-	d.Place(db).RightOf(serviced)
-	d.HAlignCenter(serviced, db)
-	d.Place(ng).Below(serviced, 40)
-	d.VAlignCenter(serviced, ng)
-	d.Place(inet).LeftOf(ng, 70)
-	d.Place(cloud).RightOf(inet)
-	d.VAlignCenter(inet, cloud)
+place serviced at 130 20
+place db right-of serviced
+halign center serviced db
+place ng below serviced 40
+valign center serviced ng
+place inet left-of ng 70
+place cloud right-of inet
+valign center inet cloud
+halign center ng inet
+place client below inet 40
+valign center inet client
 
-	d.HAlignCenter(ng, inet)
-	d.Place(client).Below(inet, 40)
-	d.VAlignCenter(inet, client)
+line serviced db
+line ng serviced
+line ng inet
+line inet client`)
+}
+
+// parseDiagram returns the diagram parsed from src, see package
+// diagram. Panics on parse errors with the line in the calling source
+// file, src is expected to start on the same line as the call.
+func parseDiagram(src string) diagram.Diagram {
+	_, file, line, _ := runtime.Caller(1)
+	d, err := diagram.Parse(filepath.Base(file), strings.NewReader(src))
+	var e *diagram.Error
+	if errors.As(err, &e) {
+		e.Line += line - 1
+	}
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// loadDiagram returns the parsed diagram file, see package diagram.
+// Panics on errors.
+func loadDiagram(filename string) diagram.Diagram {
+	d, err := diagram.ParseFile(filename)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// classDiagram returns a class diagram of the package matching
+// pattern, see diagram.ClassDiagram. Panics on errors.
+func classDiagram(pattern string) *design.Diagram {
+	d, err := diagram.ClassDiagram("", pattern)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
 
-	lineBetween := func(a, b shape.Shape) {
-		arrow, _ := d.Link(a, b)
-		arrow.Head = nil
+// packageDiagram returns a diagram of packages matching pattern, see
+// diagram.PackageDiagram. Panics on errors.
+func packageDiagram(pattern string) *design.Diagram {
+	d, err := diagram.PackageDiagram("", pattern)
+	if err != nil {
+		panic(err)
 	}
-	lineBetween(serviced, db)
-	lineBetween(ng, serviced)
-	lineBetween(ng, inet)
-	lineBetween(inet, client)
+	return d
+}
 
-	src := "img/overview.svg"
-	d.SaveAs("docs/" + src)
-	return Img(Src(src))
+// diagramStyle returns attributes of diagram classes with colors
+// from the palette, so diagrams match the page colors. Classes not
+// styled by the palette keep the attributes of the draw package.
+func diagramStyle(p tokens.Palette) draw.ClassAttributes {
+	attr := make(draw.ClassAttributes)
+	for class, v := range draw.DefaultClassAttributes {
+		attr[class] = v
+	}
+	fill := func(color string) string {
+		return fmt.Sprintf(`stroke="%s" fill="%s"`, p.Stroke, color)
+	}
+	attr["external"] = fill(p.External)
+	attr["dim"] = fill(p.External)
+	attr["internet"] = fill(p.External)
+	attr["note-box"] = fill(p.Note)
+	for _, class := range []string{"component", "process", "record", "rect", "store", "hexagon"} {
+		attr[class] = fill(p.Fill)
+	}
+	for class, color := range map[string]string{
+		"red": p.Red, "green": p.Green, "blue": p.Blue,
+	} {
+		attr["area-"+class] = fmt.Sprintf(
+			`stroke="%s" stroke-width="0" fill="%s" fill-opacity="0.1"`, p.Ink, color,
+		)
+		attr["span-"+class] = fill(color) + ` rx="5" ry="5"`
+	}
+	for i, color := range p.Fills() {
+		attr[fmt.Sprintf("circle%v", i)] = fmt.Sprintf(
+			`stroke="%s" stroke-width="1" fill="%s"`, p.Stroke, color,
+		)
+	}
+	return attr
 }
 
-func colorSchemeDiagram() *Element {
-	var (
-		d      = design.NewDiagram()
-		colors = []string{
-			"#ffffff",
-			"#e2e2e2",
-			"#ffffcc",
-			"#ffcc99",
-			"#ff9999",
-			"#ccff99",
-			"#99e6ff",
+// svg returns the diagram with its class attributes, which are
+// replaced by styleDiagrams when the page is added.
+func svg(d draw.SVGWriter) string {
+	var buf bytes.Buffer
+	d.WriteSVG(&buf)
+	return buf.String()
+}
+
+var svgClass = regexp.MustCompile(`class="([^"]*)"`)
+
+// styleDiagrams replaces class attributes of diagrams in root with
+// the attributes of the class, if any, like draw.Style does.
+func styleDiagrams(root *Element, style draw.ClassAttributes) {
+	WalkElements(root, func(e *Element) {
+		for i, c := range e.Children {
+			v, ok := c.(string)
+			if !ok || !strings.HasPrefix(v, "<svg") {
+				continue
+			}
+			e.Children[i] = svgClass.ReplaceAllStringFunc(v, func(m string) string {
+				if attr, found := style[svgClass.FindStringSubmatch(m)[1]]; found {
+					return attr
+				}
+				return m
+			})
 		}
-	)
+	})
+}
+
+// colorSchemeDiagram shows the diagram fills of the palette.
+func colorSchemeDiagram(p tokens.Palette) *design.Diagram {
+	d := design.NewDiagram()
 	var last shape.Shape
-	for i, color := range colors {
-		class := fmt.Sprintf("circle%v", i)
-		v := fmt.Sprintf(`stroke="#d3d3d3" stroke-width="1" fill="%s"`, color)
-		draw.DefaultClassAttributes[class] = v
+	for i, color := range p.Fills() {
 		c := shape.NewCircle(30)
-		c.SetClass(class)
+		c.SetClass(fmt.Sprintf("circle%v", i))
 		l := shape.NewLabel(color)
 		if last == nil {
 			d.Place(c).At(20, 20)
@@ -80,7 +173,5 @@ func colorSchemeDiagram() *Element {
 		shape.Move(l, 0, 15)
 	}
 
-	src := "img/color_scheme.svg"
-	d.SaveAs("docs/" + src)
-	return Img(Src(src))
+	return d
 }
@@ -0,0 +1,477 @@
+/*
+Package diagram parses a small text language into draw/design diagrams.
+
+Each line is one statement, words are separated by spaces and text
+containing spaces is double quoted. Lines starting with # are
+comments. The first statement may select the kind of diagram,
+components (default) or sequence.
+
+Component diagrams declare shapes, lay them out and link them
+
+	caption "Service overview"
+	component srv "serviced"
+	database db postgres
+	class external db
+	place srv at 20 20
+	place db right-of srv 60
+	halign center srv db
+	line srv db
+
+Shapes are component, database, rect, note, label, process, store,
+state, internet, actor and circle, where circle takes an optional
+radius instead of a title. Layout hints are
+
+	place ID at X Y
+	place ID right-of|left-of|below|above OTHER [SPACE]
+	move ID DX DY
+	halign center|top|bottom ID...
+	valign center|left|right ID...
+
+Links, arrow or line, are drawn after all shapes are placed.
+
+Sequence diagrams declare columns and the links between them
+
+	sequence
+	column browser
+	column router
+	colwidth 140
+	link browser router "GET /"
+	group router router "Protected" blue
+*/
+package diagram
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gregoryv/draw/design"
+	"github.com/gregoryv/draw/shape"
+)
+
+// Diagram is a parsed diagram ready for rendering.
+type Diagram interface {
+	WriteSVG(io.Writer) error
+
+	// Inline returns the SVG with inlined style
+	Inline() string
+}
+
+// ParseFile parses the named .diagram file.
+func ParseFile(filename string) (Diagram, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return Parse(filename, fh)
+}
+
+// Parse parses the diagram source read from r. The filename is only
+// used in errors.
+func Parse(filename string, r io.Reader) (Diagram, error) {
+	p := &parser{
+		filename: filename,
+		shapes:   make(map[string]shape.Shape),
+	}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		p.line++
+		if strings.HasPrefix(strings.TrimSpace(s.Text()), "#") {
+			continue
+		}
+		words, err := split(s.Text())
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if err := p.statement(words[0], words[1:]); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if err := p.finish(); err != nil {
+		return nil, err
+	}
+	return p.diagram(), nil
+}
+
+// Error is a parse error at a specific line.
+type Error struct {
+	Filename string
+	Line     int
+	Msg      string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Filename, e.Line, e.Msg)
+}
+
+type parser struct {
+	filename string
+	line     int
+
+	comp *design.Diagram
+	seq  *design.SequenceDiagram
+
+	shapes map[string]shape.Shape
+	links  []link // drawn once all shapes are placed
+}
+
+type link struct {
+	line     int
+	from, to string
+	label    string
+	arrow    bool
+}
+
+func (p *parser) statement(keyword string, args []string) error {
+	if keyword == "sequence" || keyword == "components" {
+		if p.comp != nil || p.seq != nil {
+			return p.errorf("%s must be the first statement", keyword)
+		}
+		if keyword == "sequence" {
+			p.seq = design.NewSequenceDiagram()
+		} else {
+			p.comp = design.NewDiagram()
+		}
+		return nil
+	}
+	if p.comp == nil && p.seq == nil {
+		p.comp = design.NewDiagram()
+	}
+	if keyword == "caption" {
+		if err := p.want(args, 1, 1); err != nil {
+			return err
+		}
+		if p.seq != nil {
+			p.seq.SetCaption(args[0])
+		} else {
+			p.comp.SetCaption(args[0])
+		}
+		return nil
+	}
+	if p.seq != nil {
+		return p.sequence(keyword, args)
+	}
+	return p.component(keyword, args)
+}
+
+func (p *parser) component(keyword string, args []string) error {
+	switch keyword {
+	case "component", "database", "rect", "note", "label", "process",
+		"store", "state", "internet", "actor", "circle":
+		if err := p.want(args, 1, 2); err != nil {
+			return err
+		}
+		id := args[0]
+		if _, found := p.shapes[id]; found {
+			return p.errorf("%s already declared", id)
+		}
+		title := id
+		if len(args) == 2 {
+			title = args[1]
+		}
+		s, err := p.newShape(keyword, title, len(args) == 2)
+		if err != nil {
+			return err
+		}
+		p.shapes[id] = s
+		p.comp.Place(s)
+
+	case "class":
+		if err := p.want(args, 2, -1); err != nil {
+			return err
+		}
+		shapes, err := p.lookup(args[1:]...)
+		if err != nil {
+			return err
+		}
+		shape.SetClass(args[0], shapes...)
+
+	case "place":
+		return p.place(args)
+
+	case "move":
+		if err := p.want(args, 3, 3); err != nil {
+			return err
+		}
+		shapes, err := p.lookup(args[0])
+		if err != nil {
+			return err
+		}
+		d, err := p.ints(args[1], args[2])
+		if err != nil {
+			return err
+		}
+		shape.Move(shapes[0], d[0], d[1])
+
+	case "halign", "valign":
+		if err := p.want(args, 3, -1); err != nil {
+			return err
+		}
+		shapes, err := p.lookup(args[1:]...)
+		if err != nil {
+			return err
+		}
+		align, found := aligners[keyword+" "+args[0]]
+		if !found {
+			return p.errorf("cannot %s %s", keyword, args[0])
+		}
+		align(p.comp.Aligner, shapes...)
+
+	case "arrow", "line":
+		if err := p.want(args, 2, 3); err != nil {
+			return err
+		}
+		l := link{line: p.line, from: args[0], to: args[1], arrow: keyword == "arrow"}
+		if len(args) == 3 {
+			l.label = args[2]
+		}
+		p.links = append(p.links, l)
+
+	default:
+		return p.errorf("unknown statement %q", keyword)
+	}
+	return nil
+}
+
+var aligners = map[string]func(shape.Aligner, ...shape.Shape){
+	"halign center": shape.Aligner.HAlignCenter,
+	"halign top":    shape.Aligner.HAlignTop,
+	"halign bottom": shape.Aligner.HAlignBottom,
+	"valign center": shape.Aligner.VAlignCenter,
+	"valign left":   shape.Aligner.VAlignLeft,
+	"valign right":  shape.Aligner.VAlignRight,
+}
+
+func (p *parser) newShape(kind, title string, titled bool) (shape.Shape, error) {
+	switch kind {
+	case "component":
+		return shape.NewComponent(title), nil
+	case "database":
+		return shape.NewDatabase(title), nil
+	case "rect":
+		return shape.NewRect(title), nil
+	case "note":
+		return shape.NewNote(title), nil
+	case "label":
+		return shape.NewLabel(title), nil
+	case "process":
+		return shape.NewProcess(title), nil
+	case "store":
+		return shape.NewStore(title), nil
+	case "state":
+		return shape.NewState(title), nil
+	case "internet":
+		return shape.NewInternet(), nil
+	case "actor":
+		return shape.NewActor(), nil
+	default: // circle
+		radius := 20
+		if titled {
+			r, err := p.ints(title)
+			if err != nil {
+				return nil, err
+			}
+			radius = r[0]
+		}
+		return shape.NewCircle(radius), nil
+	}
+}
+
+func (p *parser) place(args []string) error {
+	if err := p.want(args, 3, 4); err != nil {
+		return err
+	}
+	shapes, err := p.lookup(args[0])
+	if err != nil {
+		return err
+	}
+	adjust := shape.NewAdjuster(shapes[0])
+	if args[1] == "at" {
+		if len(args) != 4 {
+			return p.errorf("expected place ID at X Y")
+		}
+		xy, err := p.ints(args[2], args[3])
+		if err != nil {
+			return err
+		}
+		adjust.At(xy[0], xy[1])
+		return nil
+	}
+	other, err := p.lookup(args[2])
+	if err != nil {
+		return err
+	}
+	space, err := p.ints(args[3:]...)
+	if err != nil {
+		return err
+	}
+	switch args[1] {
+	case "right-of":
+		adjust.RightOf(other[0], space...)
+	case "left-of":
+		adjust.LeftOf(other[0], space...)
+	case "below":
+		adjust.Below(other[0], space...)
+	case "above":
+		adjust.Above(other[0], space...)
+	default:
+		return p.errorf("unknown position %q", args[1])
+	}
+	return nil
+}
+
+func (p *parser) sequence(keyword string, args []string) error {
+	switch keyword {
+	case "column":
+		if err := p.want(args, 1, 1); err != nil {
+			return err
+		}
+		p.seq.Add(args[0])
+		p.shapes[args[0]] = nil
+
+	case "colwidth":
+		if err := p.want(args, 1, 1); err != nil {
+			return err
+		}
+		w, err := p.ints(args[0])
+		if err != nil {
+			return err
+		}
+		p.seq.ColWidth = w[0]
+
+	case "link":
+		if err := p.want(args, 3, 3); err != nil {
+			return err
+		}
+		if err := p.columns(args[0], args[1]); err != nil {
+			return err
+		}
+		p.seq.Link(args[0], args[1], args[2])
+
+	case "group":
+		if err := p.want(args, 4, 4); err != nil {
+			return err
+		}
+		if err := p.columns(args[0], args[1]); err != nil {
+			return err
+		}
+		p.seq.Group(args[0], args[1], args[2], args[3])
+
+	default:
+		return p.errorf("unknown statement %q", keyword)
+	}
+	return nil
+}
+
+// finish draws all links, now that shapes are in place.
+func (p *parser) finish() error {
+	for _, l := range p.links {
+		p.line = l.line
+		shapes, err := p.lookup(l.from, l.to)
+		if err != nil {
+			return err
+		}
+		var label []string
+		if l.label != "" {
+			label = append(label, l.label)
+		}
+		arrow, _ := p.comp.Link(shapes[0], shapes[1], label...)
+		if !l.arrow {
+			arrow.Head = nil
+		}
+	}
+	return nil
+}
+
+func (p *parser) diagram() Diagram {
+	if p.seq != nil {
+		return p.seq
+	}
+	if p.comp == nil {
+		p.comp = design.NewDiagram()
+	}
+	return p.comp
+}
+
+func (p *parser) lookup(ids ...string) ([]shape.Shape, error) {
+	res := make([]shape.Shape, len(ids))
+	for i, id := range ids {
+		s, found := p.shapes[id]
+		if !found {
+			return nil, p.errorf("undeclared %s", id)
+		}
+		res[i] = s
+	}
+	return res, nil
+}
+
+func (p *parser) columns(ids ...string) error {
+	_, err := p.lookup(ids...)
+	return err
+}
+
+func (p *parser) ints(v ...string) ([]int, error) {
+	res := make([]int, len(v))
+	for i, s := range v {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, p.errorf("%q is not a number", s)
+		}
+		res[i] = n
+	}
+	return res, nil
+}
+
+// want checks number of arguments, max -1 means unlimited.
+func (p *parser) want(args []string, min, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return p.errorf("wrong number of arguments")
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &Error{
+		Filename: p.filename,
+		Line:     p.line,
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
+
+// split returns space separated words, double quoted text is one word.
+func split(line string) ([]string, error) {
+	res := make([]string, 0)
+	for {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			return res, nil
+
+		case line[0] == '"':
+			end := strings.Index(line[1:], `"`)
+			if end == -1 {
+				return nil, fmt.Errorf("missing end quote")
+			}
+			res = append(res, line[1:end+1])
+			line = line[end+2:]
+
+		default:
+			end := strings.IndexAny(line, " \t")
+			if end == -1 {
+				end = len(line)
+			}
+			res = append(res, line[:end])
+			line = line[end:]
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/design"
+	"github.com/gregoryv/draw/shape"
+)
+
+func TestParseFile(t *testing.T) {
+	d, err := ParseFile("testdata/overview.diagram")
+	ok, _ := asserter.NewErrors(t)
+	ok(err)
+	assert := asserter.New(t)
+	comp, isComp := d.(*design.Diagram)
+	assert(isComp).Fatalf("%T is not a component diagram", d)
+
+	var (
+		srv    *shape.Component
+		db     *shape.Database
+		arrows []*shape.Line
+		labels []string
+	)
+	for _, c := range comp.Content {
+		switch s := c.(type) {
+		case *shape.Component:
+			srv = s
+		case *shape.Database:
+			db = s
+		case *shape.Line:
+			arrows = append(arrows, s)
+		case *shape.Label:
+			labels = append(labels, s.Text())
+		}
+	}
+	assert(srv != nil && db != nil).Fatal("missing shapes")
+	assert().Equals(srv.Title, "serviced")
+	assert().Equals(db.Title, "postgres 14")
+	x, y := srv.Position()
+	assert(x == 20 && y == 20).Errorf("serviced at %v,%v", x, y)
+	dx, _ := db.Position()
+	assert().Equals(dx, x+srv.Width()+60)
+	assert().Equals(len(arrows), 1)
+	assert(arrows[0].Head != nil).Error("arrow without head")
+	assert().Equals(labels, []string{"query"})
+	assert().Contains(d.Inline(), "Service overview")
+}
+
+func TestParse_sequence(t *testing.T) {
+	d, err := Parse("seq", strings.NewReader(`sequence
+column browser
+column router
+colwidth 140
+link browser router "GET /"
+group router router "Protected" blue`))
+	ok, _ := asserter.NewErrors(t)
+	ok(err)
+	assert := asserter.New(t)
+	assert().Contains(d.Inline(), "GET /")
+}
+
+func TestParse_errors(t *testing.T) {
+	cases := map[string]string{
+		"component a\nplace b at 1 2":       "x:2: undeclared b",
+		"component a\n\nmove a 1 y":         `x:3: "y" is not a number`,
+		"label a \"no end":                  "x:1: missing end quote",
+		"component a\ncomponent a":          "x:2: a already declared",
+		"component a\nline a b":             "x:2: undeclared b",
+		"circle a\nhalign middle a a":       "x:2: cannot halign middle",
+		"component a\nsequence":             "x:2: sequence must be the first statement",
+		"sequence\ncolumn a\nlink a b \"\"": "x:3: undeclared b",
+		"fly away":                          `x:1: unknown statement "fly"`,
+	}
+	for src, exp := range cases {
+		_, err := Parse("x", strings.NewReader(src))
+		if err == nil || err.Error() != exp {
+			t.Errorf("%q\ngot:      %v\nexpected: %s", src, err, exp)
+		}
+	}
+}
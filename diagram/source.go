@@ -0,0 +1,257 @@
+package diagram
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/gregoryv/draw/design"
+	"github.com/gregoryv/draw/shape"
+	"golang.org/x/tools/go/packages"
+)
+
+// ClassDiagram returns a diagram of exported interfaces and types
+// declared in the package matching pattern, e.g. ./example/visitor.
+// The pattern is resolved by the go command in dir, the working
+// directory if empty. Arrows point from types to the interfaces they,
+// or pointers to them, implement.
+func ClassDiagram(dir, pattern string) (*design.Diagram, error) {
+	// type checked from source, including dependencies, so no export
+	// data of the go command is needed
+	pkgs, err := load(dir, pattern, packages.NeedTypes|packages.NeedSyntax|
+		packages.NeedImports|packages.NeedDeps)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%s: found %v packages", pattern, len(pkgs))
+	}
+	p := pkgs[0].Types
+
+	named := make([]*types.Named, 0)
+	for _, name := range p.Scope().Names() {
+		obj, ok := p.Scope().Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || obj.IsAlias() {
+			continue
+		}
+		if t, ok := obj.Type().(*types.Named); ok {
+			named = append(named, t)
+		}
+	}
+	sort.Slice(named, func(i, j int) bool { // in order of declaration
+		return named[i].Obj().Pos() < named[j].Obj().Pos()
+	})
+
+	qualifier := types.RelativeTo(p)
+	d := design.NewDiagram()
+	records := make(map[*types.Named]*shape.Record)
+	var ifaces, others []shape.Shape
+	for _, t := range named {
+		r := shape.NewRecord(t.Obj().Name())
+		records[t] = r
+		if types.IsInterface(t) {
+			ifaces = append(ifaces, r)
+		} else {
+			others = append(others, r)
+			r.Fields = fields(t, qualifier)
+		}
+		r.Methods = signatures(t, qualifier)
+	}
+	y := placeRows(d, 20, ifaces)
+	placeRows(d, y+70, others)
+	for _, t := range named {
+		for _, i := range named {
+			if !implements(t, i) {
+				continue
+			}
+			arrow := shape.NewArrowBetween(records[t], records[i])
+			arrow.SetClass("implements-arrow")
+			arrow.Head.SetClass("implements-arrow-head")
+			d.Place(arrow)
+		}
+	}
+	return d, nil
+}
+
+// implements returns true if t, or a pointer to t, implements the non
+// empty interface i. Generic types are never implementing.
+func implements(t, i *types.Named) bool {
+	iface, ok := i.Underlying().(*types.Interface)
+	if !ok || types.IsInterface(t) || iface.NumMethods() == 0 ||
+		t.TypeParams().Len() > 0 || i.TypeParams().Len() > 0 {
+		return false
+	}
+	return types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface)
+}
+
+// fields returns exported struct fields of t as "Name Type", embedded
+// fields by their type only.
+func fields(t *types.Named, q types.Qualifier) []string {
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	res := make([]string, 0)
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		switch {
+		case !f.Exported():
+		case f.Embedded():
+			res = append(res, types.TypeString(f.Type(), q))
+		default:
+			res = append(res, f.Name()+" "+types.TypeString(f.Type(), q))
+		}
+	}
+	return res
+}
+
+// signatures returns the exported methods of t, including embedded
+// and promoted ones, as "Name(ParamTypes)".
+func signatures(t *types.Named, q types.Qualifier) []string {
+	var typ types.Type = t
+	if !types.IsInterface(t) {
+		typ = types.NewPointer(t)
+	}
+	mset := types.NewMethodSet(typ)
+	res := make([]string, 0)
+	for i := 0; i < mset.Len(); i++ {
+		fn := mset.At(i).Obj()
+		if !fn.Exported() {
+			continue
+		}
+		params := fn.Type().(*types.Signature).Params()
+		v := make([]string, params.Len())
+		for j := range v {
+			v[j] = types.TypeString(params.At(j).Type(), q)
+		}
+		res = append(res, fn.Name()+"("+strings.Join(v, ", ")+")")
+	}
+	return res
+}
+
+// PackageDiagram returns a diagram of packages matching pattern,
+// e.g. ./example/behavior/..., with arrows from each package to the
+// packages it imports from the same module. Importing packages are
+// placed above imported ones. The pattern is resolved by the go
+// command in dir, the working directory if empty.
+func PackageDiagram(dir, pattern string) (*design.Diagram, error) {
+	loaded, err := load(dir, pattern, packages.NeedImports|packages.NeedModule)
+	if err != nil {
+		return nil, err
+	}
+	var module string
+	imports := make(map[string][]string) // package path to imports
+	for _, p := range loaded {
+		if p.Module == nil {
+			return nil, fmt.Errorf("%s: not in a module", p.PkgPath)
+		}
+		module = p.Module.Path
+		imports[p.PkgPath] = make([]string, 0)
+		for v := range p.Imports {
+			if v == module || strings.HasPrefix(v, module+"/") {
+				imports[p.PkgPath] = append(imports[p.PkgPath], v)
+			}
+		}
+		sort.Strings(imports[p.PkgPath])
+	}
+
+	pkgs := make([]string, 0, len(imports))
+	for pkg := range imports {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	levels := make(map[string]int)
+	var levelOf func(pkg string, seen map[string]bool) int
+	levelOf = func(pkg string, seen map[string]bool) int {
+		if seen[pkg] {
+			return 0 // import cycle, not compilable anyway
+		}
+		seen[pkg] = true
+		var max int
+		for _, imp := range imports[pkg] {
+			if _, found := imports[imp]; !found {
+				continue // outside dir
+			}
+			if l := levelOf(imp, seen) + 1; l > max {
+				max = l
+			}
+		}
+		delete(seen, pkg)
+		return max
+	}
+	var top int
+	for _, pkg := range pkgs {
+		levels[pkg] = levelOf(pkg, make(map[string]bool))
+		if levels[pkg] > top {
+			top = levels[pkg]
+		}
+	}
+
+	d := design.NewDiagram()
+	components := make(map[string]shape.Shape)
+	y := 20
+	for level := top; level >= 0; level-- {
+		row := make([]shape.Shape, 0)
+		for _, pkg := range pkgs {
+			if levels[pkg] == level {
+				c := shape.NewComponent(strings.TrimPrefix(pkg, module+"/"))
+				components[pkg] = c
+				row = append(row, c)
+			}
+		}
+		y = placeRows(d, y, row) + 60
+	}
+	for _, pkg := range pkgs {
+		for _, imp := range imports[pkg] {
+			if to, found := components[imp]; found {
+				d.Link(components[pkg], to)
+			}
+		}
+	}
+	return d, nil
+}
+
+// load returns the packages matching pattern, failing on the first
+// package error, e.g. syntax or type errors.
+func load(dir, pattern string, mode packages.LoadMode) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | mode,
+		Dir:  dir,
+	}, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("%s: no packages", pattern)
+	}
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			return nil, p.Errors[0]
+		}
+	}
+	return pkgs, nil
+}
+
+// maxWidth of generated diagrams, wider rows are wrapped
+const maxWidth = 600
+
+// placeRows places shapes from left to right starting at y, wrapping
+// rows wider than maxWidth. Returns y below the last row.
+func placeRows(d *design.Diagram, y int, shapes []shape.Shape) int {
+	x, h := 20, 0
+	for _, s := range shapes {
+		adjust := d.Place(s)
+		if x > 20 && x+s.Width() > maxWidth {
+			x = 20
+			y += h + 40
+			h = 0
+		}
+		adjust.At(x, y)
+		x += s.Width() + 40
+		if s.Height() > h {
+			h = s.Height()
+		}
+	}
+	return y + h
+}
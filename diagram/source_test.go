@@ -0,0 +1,51 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/shape"
+)
+
+func TestClassDiagram(t *testing.T) {
+	d, err := ClassDiagram("testdata/mod", "./b")
+	ok, bad := asserter.NewErrors(t)
+	ok(err)
+	records := make(map[string]*shape.Record)
+	for _, c := range d.Content {
+		if r, isRecord := c.(*shape.Record); isRecord {
+			records[r.Title] = r
+		}
+	}
+	assert := asserter.New(t)
+	assert().Equals(len(records), 5)
+	assert().Equals(records["Person"].Fields, []string{"Nick string"})
+	assert().Equals(records["Employee"].Fields, []string{"Person", "Title string"})
+	assert().Equals(records["Employee"].Methods, []string{"Name()", "Rename(string)"})
+	assert().Equals(records["Robot"].Methods, []string{"Beep(int, int)", "Name()"})
+	// Person and Employee implement Named and Renamer, Robot.Name has
+	// the wrong signature
+	assert().Equals(strings.Count(d.String(), `class="implements-arrow"`), 4)
+
+	_, err = ClassDiagram("testdata/mod", "./nosuch")
+	bad(err)
+}
+
+func TestPackageDiagram(t *testing.T) {
+	d, err := PackageDiagram("testdata/mod", "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := d.String()
+	assert := asserter.New(t)
+	a, b := strings.Index(svg, ">a<"), strings.Index(svg, ">b<")
+	assert(a != -1 && b != -1 && a < b).Errorf("expected importing a above b\n%s", svg)
+	assert().Contains(svg, ">c<")
+	// b is imported by two files of a, c imports b only in a file
+	// excluded by build constraints
+	assert().Equals(strings.Count(svg, `class="arrow"`), 1)
+
+	_, err = PackageDiagram(t.TempDir(), "./...")
+	assert(err != nil).Error("expected error without go.mod")
+}
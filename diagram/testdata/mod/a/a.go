@@ -0,0 +1,9 @@
+package a
+
+import (
+	"fmt"
+
+	"example.com/mod/b"
+)
+
+func Greet(n b.Named) { fmt.Println("Hello", n.Name()) }
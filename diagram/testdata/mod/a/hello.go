@@ -0,0 +1,5 @@
+package a
+
+import "example.com/mod/b"
+
+func Hello(n b.Named) string { return "Hello " + n.Name() }
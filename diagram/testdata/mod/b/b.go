@@ -0,0 +1,28 @@
+package b
+
+type Named interface {
+	Name() string
+}
+
+type Renamer interface {
+	Named
+	Rename(string)
+}
+
+type Person struct {
+	Nick string
+	age  int
+}
+
+func (p *Person) Name() string       { return p.Nick }
+func (p *Person) Rename(nick string) { p.Nick = nick }
+
+type Employee struct {
+	Person
+	Title string
+}
+
+type Robot struct{}
+
+func (Robot) Beep(times, volume int) {}
+func (Robot) Name() int              { return 0 }
@@ -0,0 +1,7 @@
+//go:build ignore
+
+package c
+
+import "example.com/mod/b"
+
+var _ b.Named
@@ -0,0 +1,119 @@
+package diagram
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gregoryv/draw/design"
+)
+
+// NewRecorder returns a recorder of calls starting in the given
+// component, e.g. "test". Component names must not contain spaces.
+func NewRecorder(start string) *Recorder {
+	return &Recorder{
+		columns: []string{start},
+		stack:   []string{start},
+	}
+}
+
+// Recorder records calls between instrumented components, typically
+// wrappers of interfaces used in a test
+//
+//	func (me *tracedRole) ListFlightplans() []Flightplan {
+//		defer me.rec.Enter("role", "ListFlightplans()")()
+//		return me.Role.ListFlightplans()
+//	}
+//
+// Calls are expected to be made from one goroutine.
+type Recorder struct {
+	mu      sync.Mutex
+	columns []string
+	stack   []string // of entered components
+	calls   []call
+}
+
+type call struct {
+	from, to, text string
+}
+
+// Enter records a call from the current component to the named
+// component, which becomes current until the returned func is called.
+func (me *Recorder) Enter(to, text string) (leave func()) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	me.record(me.stack[len(me.stack)-1], to, text)
+	me.stack = append(me.stack, to)
+	return func() {
+		me.mu.Lock()
+		me.stack = me.stack[:len(me.stack)-1]
+		me.mu.Unlock()
+	}
+}
+
+// Call records a call between two components.
+func (me *Recorder) Call(from, to, text string) {
+	me.mu.Lock()
+	me.record(from, to, text)
+	me.mu.Unlock()
+}
+
+func (me *Recorder) record(from, to, text string) {
+	me.addColumn(from)
+	me.addColumn(to)
+	me.calls = append(me.calls, call{from, to, text})
+}
+
+func (me *Recorder) addColumn(name string) {
+	for _, c := range me.columns {
+		if c == name {
+			return
+		}
+	}
+	me.columns = append(me.columns, name)
+}
+
+// SequenceDiagram returns the recorded calls as a sequence diagram
+// with components in order of appearance.
+func (me *Recorder) SequenceDiagram() *design.SequenceDiagram {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	d := design.NewSequenceDiagram()
+	d.AddColumns(me.columns...)
+	for _, c := range me.calls {
+		d.Link(c.from, c.to, c.text)
+	}
+	return d
+}
+
+// WriteTo writes the recorded calls as a sequence diagram source, see
+// Parse.
+func (me *Recorder) WriteTo(w io.Writer) (int64, error) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	var buf strings.Builder
+	buf.WriteString("sequence\n")
+	for _, c := range me.columns {
+		fmt.Fprintf(&buf, "column %s\n", c)
+	}
+	for _, c := range me.calls {
+		text := strings.ReplaceAll(c.text, `"`, "'")
+		fmt.Fprintf(&buf, "link %s %s \"%s\"\n", c.from, c.to, text)
+	}
+	n, err := io.WriteString(w, buf.String())
+	return int64(n), err
+}
+
+// SaveAs writes the recorded calls as source to the named file, e.g.
+// from a test keeping a .diagram file in sync with the code.
+func (me *Recorder) SaveAs(filename string) error {
+	fh, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	_, err = me.WriteTo(fh)
+	return err
+}
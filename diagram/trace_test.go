@@ -0,0 +1,34 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder("test")
+	leave := rec.Enter("router", "GET /")
+	rec.Enter("role", `List("all")`)()
+	leave()
+	rec.Call("test", "test", "check")
+
+	var buf strings.Builder
+	rec.WriteTo(&buf)
+	exp := `sequence
+column test
+column router
+column role
+link test router "GET /"
+link router role "List('all')"
+link test test "check"
+`
+	if got := buf.String(); got != exp {
+		t.Errorf("got\n%s\nexpected\n%s", got, exp)
+	}
+	if _, err := Parse("rec", strings.NewReader(exp)); err != nil {
+		t.Error(err)
+	}
+	if svg := rec.SequenceDiagram().String(); !strings.Contains(svg, "GET /") {
+		t.Error(svg)
+	}
+}
@@ -9,12 +9,14 @@ import (
 )
 
 func init() {
+	// exercise: declare flags i, s, b and d of types int, string, bool and time.Duration
 	var (
 		n = flag.Int("i", 7, "integer")
 		s = flag.String("s", "hi", "string")
 		b = flag.Bool("b", false, "bool")
 		d = flag.Duration("d", time.Second, "time.Duration")
 	)
+	// exercise: end
 	flag.Parse()
 	println(*n, *s, *b, *d)
 }
@@ -16,8 +16,10 @@ func init() {
 		Year:  2021,
 	}
 
+	// exercise: encode car as json to stdout
 	enc := json.NewEncoder(os.Stdout)
 	if err := enc.Encode(car); err != nil {
 		log.Fatal(err)
 	}
+	// exercise: end
 }
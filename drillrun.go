@@ -0,0 +1,276 @@
+package website
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	. "github.com/gregoryv/web"
+)
+
+// NewDrillRunner returns a runner of the given drill files. Drills must
+// be compiled with Build before they can be run.
+func NewDrillRunner(filenames ...string) *DrillRunner {
+	drills := make(map[string]string)
+	for _, filename := range filenames {
+		drills[drillName(filename)] = filename
+	}
+	return &DrillRunner{
+		CPUTime:    2 * time.Second,
+		MaxOutput:  64 << 10,
+		MaxRequest: 64 << 10,
+		MaxRuns:    runtime.NumCPU(),
+		drills:     drills,
+	}
+}
+
+// DrillRunner executes compiled drills in temporary directories with
+// limited cpu time, output and concurrent runs. It serves results as
+// JSON.
+//
+// By default drills run as the user of the runner with an empty
+// environment, but have full access to the filesystem and network.
+// Set Isolate, or start the runner inside a container, when serving
+// drills you do not trust.
+type DrillRunner struct {
+	// CPUTime is the cpu time limit of each run, the run is also
+	// stopped after twice the duration of wall time.
+	CPUTime time.Duration
+
+	// MaxOutput is the number of bytes kept of stdout and stderr
+	// respectively, the run is stopped if exceeded.
+	MaxOutput int
+
+	// MaxRequest is the largest request body served.
+	MaxRequest int64
+
+	// MaxRuns is the number of drills running at the same time, more
+	// fail with ErrBusy. Set before Build.
+	MaxRuns int
+
+	// Isolate runs drills in new pid, ipc, uts and network namespaces
+	// without network access. A runner started as root runs drills as
+	// user nobody, otherwise a new user namespace maps nobody to the
+	// runner user. Linux only, Build fails on other systems.
+	Isolate bool
+
+	drills map[string]string // name to filename
+	bin    string            // directory of compiled drills
+	runs   chan struct{}     // semaphore of running drills
+}
+
+// Build compiles all drills rewritten to package main. Imports of
+// drills are resolved using the module of the working directory.
+func (me *DrillRunner) Build() error {
+	if me.Isolate && !canIsolate {
+		return fmt.Errorf("build: isolation not supported on %s", runtime.GOOS)
+	}
+	me.runs = make(chan struct{}, max(1, me.MaxRuns))
+	bin, err := os.MkdirTemp("", "drillrun")
+	if err != nil {
+		return err
+	}
+	// readable by nobody when isolated
+	if err := os.Chmod(bin, 0755); err != nil {
+		return err
+	}
+	me.bin = bin
+	for name, filename := range me.drills {
+		scriptFile := filepath.Join(bin, "src", name, "drillrun_"+name+".go")
+		if err := writeScript(filename, scriptFile); err != nil {
+			return err
+		}
+		cmd := exec.Command("go", "build", "-o", filepath.Join(bin, name), scriptFile)
+		out, err := cmd.CombinedOutput()
+		os.RemoveAll(filepath.Dir(scriptFile))
+		if err != nil {
+			return fmt.Errorf("build %s: %w\n%s", filename, err, out)
+		}
+	}
+	return nil
+}
+
+// Close removes all compiled drills.
+func (me *DrillRunner) Close() error {
+	if me.bin == "" {
+		return nil
+	}
+	return os.RemoveAll(me.bin)
+}
+
+// ServeHTTP runs the requested drill. Requests are either JSON encoded
+// RunRequest or form values drill and args.
+func (me *DrillRunner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, me.MaxRequest)
+	var req RunRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			badRequest(w, err)
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			badRequest(w, err)
+			return
+		}
+		req.Drill = r.FormValue("drill")
+		req.Args = strings.Fields(r.FormValue("args"))
+	}
+	res, err := me.Run(r.Context(), req.Drill, req.Args...)
+	switch {
+	case errors.Is(err, ErrNoSuchDrill):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, ErrBusy):
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	json.NewEncoder(w).Encode(res)
+}
+
+// badRequest replies with status 413 if the body is too large, 400
+// otherwise.
+func badRequest(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+var (
+	ErrNoSuchDrill = errors.New("no such drill")
+	ErrBusy        = errors.New("too many runs")
+)
+
+// Run executes the named drill with args in a new temporary directory.
+// The directory contains the drill source for drills reading their own
+// file. Fails with ErrBusy if MaxRuns drills are already running.
+func (me *DrillRunner) Run(ctx context.Context, name string, args ...string) (*RunResult, error) {
+	filename, found := me.drills[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrNoSuchDrill, name)
+	}
+	select {
+	case me.runs <- struct{}{}:
+		defer func() { <-me.runs }()
+	default:
+		return nil, ErrBusy
+	}
+	src, err := readDrillMain(filename)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "drill")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	// writable by nobody when isolated
+	if err := os.Chmod(dir, 0777); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(
+		filepath.Join(dir, filepath.Base(filename)), src, 0644,
+	); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*me.CPUTime)
+	defer cancel()
+	secs := fmt.Sprint(int(math.Max(1, math.Ceil(me.CPUTime.Seconds()))))
+	cmd := exec.CommandContext(ctx, "/bin/sh",
+		append([]string{
+			"-c", `ulimit -t "$0" && exec "$@"`, secs, filepath.Join(me.bin, name),
+		}, args...)...,
+	)
+	cmd.Dir = dir
+	cmd.Env = []string{"HOME=" + dir, "TMPDIR=" + dir}
+	if me.Isolate {
+		isolate(cmd)
+	}
+	stdout := &limitedBuffer{max: me.MaxOutput}
+	stderr := &limitedBuffer{max: me.MaxOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	res := &RunResult{Drill: name}
+	if err := cmd.Run(); err != nil {
+		res.Error = err.Error()
+	}
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	res.Stdout = stdout.String()
+	res.Stderr = stderr.String()
+	res.Truncated = stdout.truncated || stderr.truncated
+	return res, nil
+}
+
+type RunRequest struct {
+	Drill string   `json:"drill"`
+	Args  []string `json:"args"`
+}
+
+type RunResult struct {
+	Drill     string `json:"drill"`
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	ExitCode  int    `json:"exitCode"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// limitedBuffer keeps max bytes and fails writes once full, which
+// stops the writing process.
+type limitedBuffer struct {
+	strings.Builder
+	max       int
+	truncated bool
+}
+
+func (me *limitedBuffer) Write(p []byte) (int, error) {
+	left := me.max - me.Len()
+	if len(p) > left {
+		me.Builder.Write(p[:left])
+		me.truncated = true
+		return left, fmt.Errorf("output exceeds %v bytes", me.max)
+	}
+	return me.Builder.Write(p)
+}
+
+// runForm returns a form posting drill and args to the runner at
+// the given url.
+func runForm(url, filename, args string) *Element {
+	return Form(Method("post"), Action(url),
+		Input(Type("hidden"), Name("drill"), Value(drillName(filename))),
+		Input(Type("text"), Name("args"), Value(args)),
+		Input(Type("submit"), Value("Run")),
+	)
+}
+
+// drillName returns the filename without directory and extension.
+func drillName(filename string) string {
+	base := filepath.Base(filename)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
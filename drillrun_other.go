@@ -0,0 +1,9 @@
+//go:build !linux
+
+package website
+
+import "os/exec"
+
+const canIsolate = false
+
+func isolate(cmd *exec.Cmd) {}
@@ -0,0 +1,147 @@
+package website
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDrillRunner(t *testing.T) {
+	runner := NewDrillRunner("drill/flag_types.go", "drill/slurp_file.go")
+	if err := runner.Build(); err != nil {
+		t.Fatal(err)
+	}
+	defer runner.Close()
+	srv := httptest.NewServer(runner)
+	defer srv.Close()
+
+	run := func(body, contentType string) *RunResult {
+		t.Helper()
+		resp, err := http.Post(srv.URL, contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatal(resp.Status)
+		}
+		var res RunResult
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		return &res
+	}
+
+	res := run(`{"drill":"flag_types","args":["-i","3"]}`, "application/json")
+	if res.Stderr != "3 hi false 1000000000\n" || res.ExitCode != 0 {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	form := url.Values{"drill": {"slurp_file"}}.Encode()
+	res = run(form, "application/x-www-form-urlencoded")
+	if res.Stderr == "" || res.ExitCode != 0 {
+		t.Errorf("slurp_file should read its own source %+v", res)
+	}
+
+	res = run(`{"drill":"flag_types","args":["-i","x"]}`, "application/json")
+	if res.ExitCode == 0 {
+		t.Error("expected failure on bad flag")
+	}
+
+	runner.MaxOutput = 4
+	res = run(`{"drill":"flag_types"}`, "application/json")
+	if !res.Truncated || len(res.Stderr) != 4 {
+		t.Errorf("output not limited %+v", res)
+	}
+
+	resp, _ := http.Post(srv.URL, "application/json", strings.NewReader(`{"drill":"x"}`))
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("unknown drill:", resp.Status)
+	}
+	runner.drills["gone"] = "drill/gone.go"
+	resp, _ = http.Post(srv.URL, "application/json", strings.NewReader(`{"drill":"gone"}`))
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Error("failed run:", resp.Status)
+	}
+	resp, _ = http.Get(srv.URL)
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Error("GET:", resp.Status)
+	}
+
+	runner.MaxRequest = 16
+	long := strings.Repeat("x", 32)
+	for contentType, body := range map[string]string{
+		"application/json":                  `{"drill":"` + long + `"}`,
+		"application/x-www-form-urlencoded": "drill=" + long,
+	} {
+		resp, _ = http.Post(srv.URL, contentType, strings.NewReader(body))
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Error(contentType, "too large:", resp.Status)
+		}
+	}
+	runner.MaxRequest = 1 << 10
+
+	for i := 0; i < cap(runner.runs); i++ {
+		runner.runs <- struct{}{}
+	}
+	resp, _ = http.Post(srv.URL, "application/json", strings.NewReader(`{"drill":"flag_types"}`))
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Error("busy:", resp.Status)
+	}
+	for i := 0; i < cap(runner.runs); i++ {
+		<-runner.runs
+	}
+	if res := run(`{"drill":"flag_types"}`, "application/json"); res.ExitCode != 0 {
+		t.Errorf("run after busy %+v", res)
+	}
+}
+
+func TestDrillRunner_Isolate(t *testing.T) {
+	if !canIsolate {
+		t.Skip("isolation not supported on", runtime.GOOS)
+	}
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	filename := filepath.Join(t.TempDir(), "whoami.go")
+	os.WriteFile(filename, []byte(`package drill
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+func init() {
+	_, err := net.Dial("tcp", "`+srv.Listener.Addr().String()+`")
+	fmt.Println(os.Getuid(), err == nil)
+}
+`), 0644)
+
+	for _, isolated := range []bool{false, true} {
+		runner := NewDrillRunner(filename)
+		runner.Isolate = isolated
+		if err := runner.Build(); err != nil {
+			t.Fatal(err)
+		}
+		defer runner.Close()
+		res, err := runner.Run(context.Background(), "whoami")
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := fmt.Sprintln(os.Getuid(), true)
+		if isolated {
+			exp = fmt.Sprintln(nobody, false)
+		}
+		if res.Stdout != exp {
+			t.Errorf("isolated %v: got %q, expected %q %+v", isolated, res.Stdout, exp, res)
+		}
+	}
+}
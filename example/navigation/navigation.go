@@ -0,0 +1,40 @@
+/*
+Navigation system of the space ship in the requirements engineering
+specification, implemented as far as needed to verify requirements
+with tests, see cmd/verifyspec.
+*/
+package navigation
+
+import (
+	"math"
+	"sort"
+)
+
+// Planet is a possible destination at a position in space.
+type Planet struct {
+	Name    string
+	X, Y, Z float64
+	Viable  bool
+}
+
+// Distance returns the straight distance between the planets.
+func (me Planet) Distance(p Planet) float64 {
+	return math.Sqrt(sq(me.X-p.X) + sq(me.Y-p.Y) + sq(me.Z-p.Z))
+}
+
+func sq(v float64) float64 { return v * v }
+
+// Destinations returns viable planets, closest to the ship position
+// first.
+func Destinations(ship Planet, planets []Planet) []Planet {
+	res := make([]Planet, 0, len(planets))
+	for _, p := range planets {
+		if p.Viable {
+			res = append(res, p)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return ship.Distance(res[i]) < ship.Distance(res[j])
+	})
+	return res
+}
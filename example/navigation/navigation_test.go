@@ -0,0 +1,23 @@
+package navigation
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/sogvin/website/spec/spectest"
+)
+
+func TestDestinations(t *testing.T) {
+	spectest.Verifies(t, "R4")
+	var (
+		ship    = Planet{Name: "ship"}
+		far     = Planet{Name: "far", X: 10, Viable: true}
+		near    = Planet{Name: "near", Y: -2, Z: 1, Viable: true}
+		nearest = Planet{Name: "nearest", X: 1}
+		assert  = asserter.New(t)
+	)
+	got := Destinations(ship, []Planet{far, nearest, near})
+	assert().Equals(len(got), 2)
+	assert().Equals(got[0].Name, "near")
+	assert().Equals(got[1].Name, "far")
+}
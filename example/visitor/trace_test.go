@@ -0,0 +1,74 @@
+package visitor
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/sogvin/website/diagram"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// Test_trace_CalcArea records the calls made when calculating the
+// area of a circle, used in the diagram of the visitor pattern. Run
+// with -update to save a changed diagram.
+func Test_trace_CalcArea(t *testing.T) {
+	rec := diagram.NewRecorder("test")
+	circle := &tracedShape{Shape: &Circle{radius: 3}, rec: rec}
+
+	leave := rec.Enter("CalcArea", "CalcArea(circle)")
+	CalcArea(circle)
+	leave()
+
+	const golden = "testdata/calc_area.diagram"
+	if *update {
+		if err := rec.SaveAs(golden); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filename := filepath.Join(t.TempDir(), "calc_area.diagram")
+	if err := rec.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filename)
+	exp, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := asserter.New(t)
+	assert().Equals(string(got), string(exp))
+}
+
+// tracedShape records calls to Accept and to the given behavior.
+type tracedShape struct {
+	Shape
+	rec *diagram.Recorder
+}
+
+func (me *tracedShape) Accept(b ShapeBehavior) {
+	defer me.rec.Enter("shape", "Accept(ShapeBehavior)")()
+	me.Shape.Accept(&tracedBehavior{b, me.rec})
+}
+
+type tracedBehavior struct {
+	ShapeBehavior
+	rec *diagram.Recorder
+}
+
+func (me *tracedBehavior) Square(s *Square) {
+	defer me.rec.Enter("behavior", "Square(*Square)")()
+	me.ShapeBehavior.Square(s)
+}
+
+func (me *tracedBehavior) Circle(c *Circle) {
+	defer me.rec.Enter("behavior", "Circle(*Circle)")()
+	me.ShapeBehavior.Circle(c)
+}
+
+func (me *tracedBehavior) Rectangle(r *Rectangle) {
+	defer me.rec.Enter("behavior", "Rectangle(*Rectangle)")()
+	me.ShapeBehavior.Rectangle(r)
+}
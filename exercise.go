@@ -0,0 +1,142 @@
+package website
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// exerciseMark starts a region in a drill which is hidden on the
+// exercise page. The text after the mark is used as hint and the
+// region ends with "// exercise: end".
+const exerciseMark = "// exercise:"
+
+// hasExercise returns true if src contains exercise marked regions.
+func hasExercise(src string) bool {
+	return strings.Contains(src, exerciseMark)
+}
+
+// blankExercises returns src with each marked region replaced by a
+// TODO stub with the hint.
+func blankExercises(src string) string {
+	var buf bytes.Buffer
+	var inside bool
+	for _, line := range strings.SplitAfter(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, exerciseMark):
+			hint := strings.TrimSpace(trimmed[len(exerciseMark):])
+			if hint == "end" {
+				inside = false
+				continue
+			}
+			inside = true
+			indent := line[:strings.Index(line, exerciseMark)]
+			buf.WriteString(indent + "// TODO " + hint + "\n")
+		case inside:
+			continue
+		default:
+			buf.WriteString(line)
+		}
+	}
+	return buf.String()
+}
+
+// stripExerciseMarks returns the full solution without exercise
+// marks.
+func stripExerciseMarks(src string) string {
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(src, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), exerciseMark) {
+			continue
+		}
+		buf.WriteString(line)
+	}
+	return buf.String()
+}
+
+// newExercise returns an exercise for the given drill. Expected output
+// is the result of running the drill with args. Panics if the drill
+// fails to run.
+func newExercise(args, filename string) *exercise {
+	out, err := runExample(args, filename)
+	if err != nil {
+		panic(fmt.Sprintf("%s: %v", filename, err))
+	}
+	return &exercise{
+		args:     args,
+		filename: filename,
+		expected: skipFirstLine(string(out)),
+	}
+}
+
+type exercise struct {
+	args     string
+	filename string
+	expected string
+}
+
+func (me *exercise) name() string { return drillName(me.filename) }
+
+func (me *exercise) zipFile() string      { return me.name() + "_exercise.zip" }
+func (me *exercise) solutionFile() string { return me.name() + "_solution.html" }
+
+// Links returns links to the downloadable harness and the solution
+// page.
+func (me *exercise) Links() *Element {
+	return Ul(
+		Li(A(Href(me.zipFile()), "Download exercise"), " and verify ",
+			"your solution with ", Code("go test"),
+		),
+		Li(A(Href(me.solutionFile()), "Reveal solution")),
+	)
+}
+
+// Bundle returns the exercise as a bundle in dir, containing the
+// blanked drill as a main package and a test harness.
+func (me *exercise) Bundle(dir string) *bundle {
+	b := newBundle(path.Join(dir, me.zipFile()))
+	b.load = func(b *bundle) error {
+		src, err := os.ReadFile(me.filename)
+		if err != nil {
+			return err
+		}
+		data := asMain([]byte(blankExercises(string(src))))
+		b.Add("go.mod", goMod(me.name(), data))
+		b.Add(filepath.Base(me.filename), data)
+		b.Add("exercise_test.go", me.harness())
+		return nil
+	}
+	return b
+}
+
+// harness returns a go test file which runs the learners solution and
+// compares the output with the expected.
+func (me *exercise) harness() []byte {
+	var args string
+	for _, arg := range strings.Fields(me.args) {
+		args += fmt.Sprintf(", %q", arg)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestExercise(t *testing.T) {
+	got, _ := exec.Command("go", "run", "."%s).CombinedOutput()
+	exp := %q
+	if string(got) != exp {
+		t.Errorf("\ngot:\n%%s\nexpected:\n%%s", got, exp)
+	}
+}
+`, args, me.expected)
+	return buf.Bytes()
+}
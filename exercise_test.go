@@ -0,0 +1,114 @@
+package website
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func Test_blankExercises(t *testing.T) {
+	cases := []struct {
+		txt, src, exp string
+	}{
+		{
+			txt: "no marks",
+			src: "a\nb\n",
+			exp: "a\nb\n",
+		},
+		{
+			txt: "one region",
+			src: "a\n\t// exercise: parse flags\n\tflag.Parse()\n\t// exercise: end\nb\n",
+			exp: "a\n\t// TODO parse flags\nb\n",
+		},
+		{
+			txt: "two regions",
+			src: "// exercise: one\nx\n// exercise: end\ny\n// exercise: two\nz\n// exercise: end\n",
+			exp: "// TODO one\ny\n// TODO two\n",
+		},
+		{
+			txt: "unterminated region hides rest",
+			src: "a\n// exercise: rest\nb\nc\n",
+			exp: "a\n// TODO rest\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.txt, func(t *testing.T) {
+			if got := blankExercises(c.src); got != c.exp {
+				t.Errorf("\ngot:\n%q\nexp:\n%q", got, c.exp)
+			}
+		})
+	}
+}
+
+func Test_stripExerciseMarks(t *testing.T) {
+	cases := []struct {
+		txt, src, exp string
+	}{
+		{
+			txt: "no marks",
+			src: "a\nb\n",
+			exp: "a\nb\n",
+		},
+		{
+			txt: "region kept",
+			src: "a\n\t// exercise: parse flags\n\tflag.Parse()\n\t// exercise: end\nb\n",
+			exp: "a\n\tflag.Parse()\nb\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.txt, func(t *testing.T) {
+			if got := stripExerciseMarks(c.src); got != c.exp {
+				t.Errorf("\ngot:\n%q\nexp:\n%q", got, c.exp)
+			}
+		})
+	}
+}
+
+func Test_exercise_harness(t *testing.T) {
+	drill := filepath.Join(t.TempDir(), "args.go")
+	os.WriteFile(drill, []byte(`package drill
+
+import (
+	"fmt"
+	"os"
+)
+
+var args = os.Args[1:]
+
+func init() {
+	fmt.Print("args: ")
+	// exercise: print arguments
+	fmt.Println(args)
+	// exercise: end
+}
+`), 0644)
+	ex := &exercise{args: "-n 1", filename: drill, expected: "args: [-n 1]\n"}
+	b := ex.Bundle("drill")
+	ok, bad := asserter.NewErrors(t)
+	ok(b.load(b))
+
+	dir := t.TempDir()
+	for name, data := range b.entries {
+		ok(os.WriteFile(filepath.Join(dir, name), data, 0644))
+	}
+	goTest := func() (string, error) {
+		cmd := exec.Command("go", "test", ".")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	out, err := goTest()
+	bad(err) // blanked exercise
+	assert := asserter.New(t)
+	assert().Contains(out, "--- FAIL: TestExercise")
+
+	solution, err := readDrillMain(drill)
+	ok(err)
+	ok(os.WriteFile(filepath.Join(dir, "args.go"), solution, 0644))
+	out, err = goTest()
+	ok(err)
+	assert().Contains(out, "ok")
+}
@@ -0,0 +1,103 @@
+package website
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// figure returns a figure with caption which can be referred to by
+// key. Images in content without alt text use the caption. Figures
+// are numbered when their page is added.
+func figure(key, caption string, content ...interface{}) *Element {
+	for _, c := range content {
+		if img, ok := c.(*Element); ok && img.Name == "img" && img.AttrVal("alt") == "" {
+			img.With(Alt(plainText(caption)))
+		}
+	}
+	return captioned("figure", key, caption, content...)
+}
+
+// listing returns a code listing with caption which can be referred
+// to by key. Listings are numbered when their page is added.
+func listing(key, caption string, content ...interface{}) *Element {
+	return captioned("listing", key, caption, content...)
+}
+
+func captioned(class, key, caption string, content ...interface{}) *Element {
+	return NewElement("figure", Class(class), Id(key)).With(content...).With(
+		NewElement("figcaption",
+			caption, " ", A(Class("self"), Href("#"+key), "&sect;"),
+		),
+	)
+}
+
+// number prefixes captions of figures with the given class in order
+// of appearance, starting from 1 on each page. Numbered figures are
+// registered as references below the page key and added to the list
+// of figures.
+func (me *Website) number(key, title, filename string, article *Element, class, label string) {
+	for i, e := range Query(article, "figure."+class) {
+		n := fmt.Sprintf("%s %v", label, i+1)
+		caption := MustQueryOne(e, "figcaption")
+		text := plainText(headingText(caption))
+		caption.Children = append(
+			[]interface{}{Span(Class("number"), n+".")}, caption.Children...,
+		)
+		id := e.AttrVal("id")
+		href := filename + "#" + id
+		me.register(key+"/"+id, n, href)
+		if class == "figure" {
+			me.figures = append(me.figures, &listedFigure{
+				page:    title,
+				number:  n,
+				caption: text,
+				href:    href,
+			})
+		}
+	}
+}
+
+// listedFigure is one entry in the list of figures.
+type listedFigure struct {
+	page    string
+	number  string
+	caption string
+	href    string
+}
+
+// WithListOfFigures adds a page listing all figures under References.
+func WithListOfFigures() SiteOption {
+	return func(w *Website) {
+		w.figureList = true
+	}
+}
+
+// addFigureList adds the list of figures page, if enabled, and
+// returns a link to it. Call it after all pages with figures are
+// added.
+func (me *Website) addFigureList() *Element {
+	if !me.figureList {
+		return Wrap()
+	}
+	article := Article(Class("figures"), H1("List of figures"))
+	var (
+		ul   *Element
+		page string
+	)
+	for _, f := range me.figures {
+		if f.page != page {
+			page = f.page
+			ul = Ul()
+			article.With(H2(page), ul)
+		}
+		ul.With(Li(A(Href(f.href), f.number), " ", f.caption))
+	}
+	return me.AddPage("References", article)
+}
+
+// plainText returns v without tags and with whitespace collapsed.
+func plainText(v string) string {
+	return strings.Join(strings.Fields(stripTags(v)), " ")
+}
@@ -0,0 +1,210 @@
+package website
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	. "github.com/gregoryv/web"
+)
+
+// WithFonts bundles font files found in dir with the website instead
+// of importing fonts from a remote service. With an empty dir only
+// system fonts are used. Either way saving fails if any page refers
+// to external stylesheets or fonts.
+func WithFonts(dir string) SiteOption {
+	return func(w *Website) {
+		w.offline = true
+		w.fontDir = dir
+	}
+}
+
+// fontFormats maps font file extensions to @font-face formats, in
+// order of preference.
+var fontFormats = []struct{ ext, format string }{
+	{".woff2", "woff2"},
+	{".woff", "woff"},
+	{".ttf", "truetype"},
+	{".otf", "opentype"},
+}
+
+// fontWeights maps style suffixes of font filenames to weights.
+var fontWeights = map[string]int{
+	"Thin":       100,
+	"ExtraLight": 200,
+	"Light":      300,
+	"Regular":    400,
+	"Medium":     500,
+	"SemiBold":   600,
+	"Bold":       700,
+	"ExtraBold":  800,
+	"Black":      900,
+}
+
+// fontFace is one @font-face rule, possibly with many files.
+type fontFace struct {
+	family string
+	weight int
+	style  string
+	files  []string // in order of preference
+}
+
+// loadFonts returns faces of font files in dir named like
+// SourceSansPro-BoldItalic.woff2, i.e. family in camel case and an
+// optional weight and style.
+func loadFonts(dir string) ([]*fontFace, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("load fonts: %w", err)
+	}
+	faces := make(map[string]*fontFace)
+	keys := make([]string, 0)
+	for _, format := range fontFormats {
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if e.IsDir() || !strings.EqualFold(ext, format.ext) {
+				continue
+			}
+			f := parseFontName(strings.TrimSuffix(e.Name(), ext))
+			key := fmt.Sprintf("%s %v %s", f.family, f.weight, f.style)
+			if _, found := faces[key]; !found {
+				faces[key] = f
+				keys = append(keys, key)
+			}
+			faces[key].files = append(faces[key].files, e.Name())
+		}
+	}
+	sort.Strings(keys)
+	res := make([]*fontFace, len(keys))
+	for i, key := range keys {
+		res[i] = faces[key]
+	}
+	return res, nil
+}
+
+func parseFontName(name string) *fontFace {
+	f := &fontFace{weight: 400, style: "normal"}
+	family, variant, _ := strings.Cut(name, "-")
+	if v, found := strings.CutSuffix(variant, "Italic"); found {
+		f.style = "italic"
+		variant = v
+	}
+	if w, found := fontWeights[variant]; found {
+		f.weight = w
+	}
+	f.family = splitCamelCase(family)
+	return f
+}
+
+// splitCamelCase returns words of v separated by space, keeping
+// acronyms together, e.g. IBMPlexMono as IBM Plex Mono.
+func splitCamelCase(v string) string {
+	r := []rune(v)
+	var buf strings.Builder
+	for i := range r {
+		if i > 0 && unicode.IsUpper(r[i]) {
+			lowerBefore := unicode.IsLower(r[i-1])
+			lowerAfter := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if lowerBefore || (unicode.IsUpper(r[i-1]) && lowerAfter) {
+				buf.WriteRune(' ')
+			}
+		}
+		buf.WriteRune(r[i])
+	}
+	return buf.String()
+}
+
+// addFontFaces adds @font-face rules for fonts saved in fonts/
+// relative to the stylesheet.
+func addFontFaces(css *CSS, faces []*fontFace) {
+	for _, f := range faces {
+		src := make([]string, len(f.files))
+		for i, file := range f.files {
+			src[i] = fmt.Sprintf("url(fonts/%s) format(%q)", file, fontFormat(file))
+		}
+		css.Style("@font-face",
+			fmt.Sprintf("font-family: '%s'", f.family),
+			fmt.Sprintf("font-weight: %v", f.weight),
+			"font-style: "+f.style,
+			"font-display: swap",
+			"src: "+strings.Join(src, ", "),
+		)
+	}
+}
+
+func fontFormat(filename string) string {
+	ext := filepath.Ext(filename)
+	for _, f := range fontFormats {
+		if strings.EqualFold(ext, f.ext) {
+			return f.format
+		}
+	}
+	return ""
+}
+
+// saveFonts copies bundled font files to base/fonts.
+func (me *Website) saveFonts(base string) error {
+	if me.fontDir == "" {
+		return nil
+	}
+	dir := filepath.Join(base, "fonts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, f := range me.fonts {
+		for _, file := range f.files {
+			data, err := os.ReadFile(filepath.Join(me.fontDir, file))
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(filepath.Join(dir, file), data, 0644)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// external matches references to remote resources in css
+var external = regexp.MustCompile(`@import|url\(\s*['"]?(https?:)?//`)
+
+// checkOffline returns an error if any page or theme refers to
+// external stylesheets or fonts.
+func checkOffline(pages []*Page, themes []*CSS) error {
+	for _, page := range pages {
+		for _, link := range Query(page.Element, "link") {
+			rel := link.AttrVal("rel")
+			if rel != "stylesheet" && rel != "preload" {
+				continue // e.g. canonical
+			}
+			if isRemote(link.AttrVal("href")) {
+				return fmt.Errorf("%s: external stylesheet %q",
+					page.Filename, link.AttrVal("href"),
+				)
+			}
+		}
+		for _, style := range Query(page.Element, "style") {
+			if ref := external.FindString(styleText(style)); ref != "" {
+				return fmt.Errorf("%s: external reference %q", page.Filename, ref)
+			}
+		}
+	}
+	for _, theme := range themes {
+		var buf bytes.Buffer
+		theme.WriteTo(&buf)
+		if ref := external.FindString(buf.String()); ref != "" {
+			return fmt.Errorf("%s: external reference %q", theme.Filename, ref)
+		}
+	}
+	return nil
+}
+
+func isRemote(href string) bool {
+	return strings.HasPrefix(href, "//") || strings.Contains(href, "://")
+}
@@ -0,0 +1,73 @@
+package website
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_loadFonts(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"SourceSansPro-Regular.ttf",
+		"SourceSansPro-Regular.woff2",
+		"SourceSansPro-BoldItalic.woff2",
+		"Inconsolata.woff",
+		"README.txt",
+	} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0644)
+	}
+	faces, err := loadFonts(dir)
+	ok, bad := asserter.NewErrors(t)
+	ok(err)
+	assert := asserter.New(t)
+	assert().Equals(faces, []*fontFace{
+		{"Inconsolata", 400, "normal", []string{"Inconsolata.woff"}},
+		{"Source Sans Pro", 400, "normal", []string{
+			"SourceSansPro-Regular.woff2", "SourceSansPro-Regular.ttf",
+		}},
+		{"Source Sans Pro", 700, "italic", []string{"SourceSansPro-BoldItalic.woff2"}},
+	})
+
+	css := NewCSS()
+	addFontFaces(css, faces[1:2])
+	var buf bytes.Buffer
+	css.WriteTo(&buf)
+	assert().Contains(buf.String(),
+		`src: url(fonts/SourceSansPro-Regular.woff2) format("woff2"), url(fonts/SourceSansPro-Regular.ttf) format("truetype")`,
+	)
+
+	_, err = loadFonts(filepath.Join(dir, "missing"))
+	bad(err)
+}
+
+func Test_parseFontName(t *testing.T) {
+	assert := asserter.New(t)
+	for name, exp := range map[string]string{
+		"SourceSansPro-Bold": "Source Sans Pro",
+		"IBMPlexMono-Italic": "IBM Plex Mono",
+		"PTSerif":            "PT Serif",
+		"Inconsolata":        "Inconsolata",
+		"FiraCodeNF":         "Fira Code NF",
+	} {
+		assert().Equals(parseFontName(name).family, exp)
+	}
+}
+
+func Test_checkOffline(t *testing.T) {
+	local := NewFile("a.html", Html(Head(
+		stylesheet("theme.css"),
+		Link(Rel("canonical"), Href("https://x.example/a.html")),
+	)))
+	remote := NewFile("b.html", Html(Head(stylesheet("https://x.example/a.css"))))
+	ok, bad := asserter.NewErrors(t)
+	ok(checkOffline([]*Page{local}, nil))
+	bad(checkOffline([]*Page{local, remote}, nil)) // external stylesheet
+	css := NewCSS()
+	css.Import("https://fonts.example/css")
+	bad(checkOffline(nil, []*CSS{css})) // import
+}
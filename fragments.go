@@ -153,7 +153,12 @@ func example(args string, files ...string) *Element {
 }
 
 func loadExample(filename string) *Element {
-	src := loadAs(filename, "init", "main")
+	return drillSource(filename, loadAs(filename, "init", "main"))
+}
+
+// drillSource returns title, description and code of the given drill
+// source.
+func drillSource(filename, src string) *Element {
 	i := strings.Index(src, "\n") // first line
 	fn := strings.Index(src, "\npackage")
 	var block string
@@ -187,9 +192,9 @@ func shellCommand(v string) *Element {
 	return Pre(Class("command"), Code(v))
 }
 
-func linkDrill(filename string) *Element {
+func linkDrill(dir, filename string) *Element {
 	title := drillTitle(filename)
-	return Li(A(Href(toHtmlFile(filename)), title))
+	return Li(A(Href(path.Join(dir, toHtmlFile(filepath.Base(filename)))), title))
 }
 
 func drillTitle(filename string) string {
@@ -246,20 +251,7 @@ func runExample(args string, files ...string) ([]byte, error) {
 	defer os.RemoveAll(scriptFile)
 
 	if changed(first, outfile) {
-		data, err := ioutil.ReadFile(first)
-		if err != nil {
-			return nil, err
-		}
-
-		// modify drill to contain a main func
-		data = bytes.ReplaceAll(data, []byte("func init("), []byte("func main("))
-		data = bytes.ReplaceAll(data, []byte("package drill"), []byte("package main"))
-
-		if err := os.MkdirAll(dir, 0722); err != nil {
-			return nil, err
-		}
-
-		if err := ioutil.WriteFile(scriptFile, data, 0644); err != nil {
+		if err := writeScript(first, scriptFile); err != nil {
 			return nil, err
 		}
 		log.Println(scriptFile)
@@ -291,6 +283,26 @@ func runExample(args string, files ...string) ([]byte, error) {
 	return os.ReadFile(outfile)
 }
 
+// writeScript writes the drill rewritten to package main as
+// scriptFile.
+func writeScript(filename, scriptFile string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(scriptFile), 0722); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(scriptFile, asMain(data), 0644)
+}
+
+// asMain modifies drill to contain a main func in package main.
+func asMain(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("func init("), []byte("func main("))
+	data = bytes.ReplaceAll(data, []byte("package drill"), []byte("package main"))
+	return data
+}
+
 // changed returns true if the src has been changed after the dst file
 // Returns false on stat errors
 func changed(src, dst string) bool {
@@ -1,8 +1,6 @@
 module github.com/sogvin/website
 
-go 1.21
-
-toolchain go1.21.3
+go 1.22.0
 
 require (
 	github.com/gregoryv/asserter v0.4.2
@@ -13,13 +11,16 @@ require (
 	github.com/gregoryv/qual v0.4.2
 	github.com/gregoryv/web v0.25.0
 	github.com/gregoryv/workdir v0.2.1
+	github.com/russross/blackfriday/v2 v2.1.0
+	golang.org/x/tools v0.26.0
 )
 
 require (
 	github.com/gregoryv/gocyclo v0.1.1 // indirect
 	github.com/gregoryv/nexus v0.6.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
 )
 
 replace github.com/gregoryv/navstar => ../navstar
@@ -0,0 +1,165 @@
+package website
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// Layout renders articles as complete pages with the head, header,
+// navigation and footer common to pages of one kind.
+type Layout struct {
+	Dir    string   // of pages relative to the site root, e.g. drill
+	Kind   string   // of structured data, e.g. Article or Course
+	Themes []string // stylesheets relative to the site root
+
+	// Title returns the page title given the text of the article h1
+	Title func(site *Website, right, heading string) string
+
+	// Header, Nav and Footer are optional and may return nil, root is
+	// the relative path to the site root, e.g. ../ for pages in a
+	// directory.
+	Header func(site *Website, root, right string) *Element
+	Nav    func(site *Website, root, right string) *Element
+	Footer func(site *Website) *Element
+}
+
+// Render returns the article as a page named filename in the directory
+// of the layout. Optional themes are linked after the themes of the
+// layout.
+func (me *Layout) Render(site *Website, filename, right string, article *Element, themes ...*CSS) *Page {
+	root := me.root()
+	heading := MustQueryOne(article, "h1").Text()
+	head := Head(
+		Meta(Charset("utf-8")),
+		Meta(
+			Name("viewport"),
+			Content("width=device-width, initial-scale=1.0"),
+		),
+	)
+	for _, theme := range me.Themes {
+		head.With(stylesheet(root + theme))
+	}
+	for _, theme := range themes {
+		head.With(stylesheet(root + theme.Filename))
+	}
+	head.With(
+		Title(me.Title(site, right, heading)),
+		site.metadata(me.Kind, heading, path.Join(me.Dir, filename), article),
+	)
+	body := Body()
+	if me.Header != nil {
+		body.With(me.Header(site, root, right))
+	}
+	if me.Nav != nil {
+		if nav := me.Nav(site, root, right); nav != nil {
+			body.With(nav)
+		}
+	}
+	body.With(article)
+	if me.Footer != nil {
+		body.With(me.Footer(site))
+	}
+	return NewFile(path.Join(me.Dir, filename), Html(Lang("en"), head, body))
+}
+
+// root returns the relative path from pages to the site root.
+func (me *Layout) root() string {
+	if me.Dir == "" {
+		return ""
+	}
+	return strings.Repeat("../", len(strings.Split(path.Clean(me.Dir), "/")))
+}
+
+// defaultLayouts returns the article, drill, index and print layouts.
+func defaultLayouts() map[string]*Layout {
+	themes := []string{"theme.css", "a4.css", "screen.css"}
+	return map[string]*Layout{
+		"article": {
+			Kind:   "Article",
+			Themes: themes,
+			Title:  articleTitle,
+			Header: backlink,
+			Nav:    sectionNav,
+			Footer: authorFooter,
+		},
+		"drill": {
+			Dir:    "drill",
+			Kind:   "Article",
+			Themes: themes,
+			Title: func(_ *Website, right, _ string) string {
+				return right + " - drill"
+			},
+			Header: backlink,
+			Nav:    sectionNav,
+			Footer: authorFooter,
+		},
+		"index": {
+			Kind:   "Course",
+			Themes: themes,
+			Title: func(site *Website, _, _ string) string {
+				return site.title
+			},
+			Header: func(_ *Website, root, _ string) *Element {
+				return Header(Code(
+					A(Href(root+"changelog.html"), versionField()),
+				))
+			},
+			Footer: func(*Website) *Element { return Footer() },
+		},
+		// without screen styles and navigation
+		"print": {
+			Kind:   "Article",
+			Themes: []string{"theme.css", "a4.css"},
+			Title:  articleTitle,
+			Footer: authorFooter,
+		},
+	}
+}
+
+func articleTitle(site *Website, _, heading string) string {
+	return stripTags(heading) + " - " + site.title
+}
+
+// backlink returns a header linking to the index, prefixed with right
+// if given.
+func backlink(site *Website, root, right string) *Element {
+	link := A(Href(root+"index.html"), site.title)
+	if right == "" {
+		return Header(link)
+	}
+	return Header(Code(right, " - ", link))
+}
+
+// sectionNav returns navigation to the section of the index listing
+// the page, nil without right.
+func sectionNav(_ *Website, root, right string) *Element {
+	if right == "" {
+		return nil
+	}
+	return Nav(Class("section"),
+		A(Href(root+"index.html#"+filenameFrom(right)), "&larr; ", right),
+	)
+}
+
+func authorFooter(site *Website) *Element {
+	return Footer(site.author)
+}
+
+// WithLayout adds or replaces the named layout, see AddPageAs.
+func WithLayout(name string, layout *Layout) SiteOption {
+	return func(w *Website) {
+		w.layouts[name] = layout
+	}
+}
+
+// layout returns the named layout.
+func (me *Website) layout(name string) (*Layout, error) {
+	l, found := me.layouts[name]
+	if !found {
+		return nil, fmt.Errorf("unknown layout %q", name)
+	}
+	return l, nil
+}
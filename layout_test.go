@@ -0,0 +1,95 @@
+package website
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_Layout_Render(t *testing.T) {
+	site := &Website{title: "Site", author: "me"}
+	layouts := defaultLayouts()
+	extra := NewCSS()
+	extra.Filename = "extra.css"
+
+	page := layouts["drill"].Render(site, "a.html", "Logging",
+		Article(H1("Drill")), extra,
+	)
+	assert := asserter.New(t)
+	assert().Equals(page.Filename, "drill/a.html")
+	assert().Equals(stylesheets(page), []string{
+		"../theme.css", "../a4.css", "../screen.css", "../extra.css",
+	})
+	assert().Equals(MustQueryOne(page.Element, "title").Text(), "Logging - drill")
+	assert().Equals(MustQueryOne(MustQueryOne(page.Element, "header"), "a").AttrVal("href"), "../index.html")
+	assert().Equals(MustQueryOne(MustQueryOne(page.Element, "nav"), "a").AttrVal("href"), "../index.html#logging")
+	assert().Equals(MustQueryOne(page.Element, "footer").Text(), "me")
+
+	page = layouts["article"].Render(site, "a.html", "", Article(H1("A")))
+	assert().Equals(len(Query(page.Element, "nav")), 0)
+}
+
+func Test_Layout_Render_print(t *testing.T) {
+	site := &Website{title: "Site", author: "me"}
+	page := defaultLayouts()["print"].Render(site, "a.html", "Design",
+		Article(H1("A")),
+	)
+	assert := asserter.New(t)
+	assert().Equals(page.Filename, "a.html")
+	assert().Equals(stylesheets(page), []string{"theme.css", "a4.css"})
+	assert().Equals(MustQueryOne(page.Element, "title").Text(), "A - Site")
+	assert().Equals(len(Query(page.Element, "header")), 0)
+	assert().Equals(len(Query(page.Element, "nav")), 0)
+	assert().Equals(MustQueryOne(page.Element, "footer").Text(), "me")
+}
+
+func TestWebsite_AddPageAs(t *testing.T) {
+	site := &Website{
+		refs:    make(map[string]*reference),
+		layouts: defaultLayouts(),
+	}
+	WithLayout("tag", &Layout{Dir: "tags", Title: articleTitle})(site)
+	link, err := site.AddPageAs("tag", "", Article(H1("Go"), H2("Links")))
+	ok, bad := asserter.NewErrors(t)
+	ok(err)
+
+	assert := asserter.New(t)
+	assert().Equals(site.pages[0].Filename, "tags/go.html")
+	assert().Equals(MustQueryOne(link, "a").AttrVal("href"), "tags/go.html")
+	r, found := site.refs["go/links"]
+	assert(found).Fatal("ref not registered")
+	assert().Equals(r.href, "tags/go.html#links")
+
+	_, err = site.AddPageAs("nosuch", "", Article(H1("X")))
+	bad(err)
+}
+
+func TestWebsite_AddDrill_layoutDir(t *testing.T) {
+	site := &Website{
+		refs:    make(map[string]*reference),
+		layouts: defaultLayouts(),
+	}
+	WithLayout("drill", &Layout{Dir: "practice", Title: articleTitle})(site)
+	link := site.AddDrill("Logging", "", "drill/logging.go")
+
+	assert := asserter.New(t)
+	assert().Equals(MustQueryOne(link, "a").AttrVal("href"), "practice/logging.html")
+	assert().Equals(site.pages[0].Filename, "practice/logging.html")
+	assert().Equals(site.refs["practice/logging"].href, "practice/logging.html")
+	assert().Equals(site.bundles[0].filename, "practice/logging.zip")
+	bundle := Query(site.pages[0].Element, "a")
+	assert(len(bundle) > 0).Fatal("no links on drill page")
+	assert().Equals(bundle[len(bundle)-1].AttrVal("href"), "logging.zip")
+}
+
+// stylesheets returns hrefs of linked stylesheets of the page.
+func stylesheets(page *Page) []string {
+	res := make([]string, 0)
+	for _, l := range Query(page.Element, "link") {
+		if l.AttrVal("rel") == "stylesheet" {
+			res = append(res, l.AttrVal("href"))
+		}
+	}
+	return res
+}
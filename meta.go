@@ -0,0 +1,188 @@
+package website
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"path"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// WithBaseURL sets the url where the website is published, e.g.
+// https://www.sogvin.com. It's needed for canonical links and
+// absolute urls in Open Graph and structured data.
+func WithBaseURL(url string) SiteOption {
+	return func(w *Website) {
+		w.baseURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// metadata returns head elements describing the article for search
+// engines and link previews. The description is the first paragraph,
+// the image the first one in the article and the published time from
+// the first time element with a datetime attribute.
+func (me *Website) metadata(kind, title, filename string, article *Element) *Element {
+	var (
+		name        = unescape(plainText(title))
+		description = summary(article)
+		image       = me.absolute(fromRoot(filename, firstAttr(article, "img", "src")))
+		published   = firstAttr(article, "time", "datetime")
+		author      = unescape(me.author)
+	)
+	card := "summary"
+	if image != "" {
+		card = "summary_large_image"
+	}
+	ogType := "article"
+	if kind == "Course" {
+		ogType = "website"
+	}
+	head := Wrap(
+		Meta(Name("author"), Content(escape(author))),
+		property("og:type", ogType),
+		property("og:title", name),
+		property("og:site_name", unescape(me.title)),
+		Meta(Name("twitter:card"), Content(card)),
+		Meta(Name("twitter:title"), Content(escape(name))),
+	)
+	if description != "" {
+		head.With(
+			Meta(Name("description"), Content(escape(description))),
+			property("og:description", description),
+			Meta(Name("twitter:description"), Content(escape(description))),
+		)
+	}
+	if me.baseURL != "" {
+		url := me.absolute(filename)
+		head.With(
+			Link(Rel("canonical"), Href(url)),
+			property("og:url", url),
+		)
+	}
+	if image != "" {
+		head.With(
+			property("og:image", image),
+			Meta(Name("twitter:image"), Content(image)),
+		)
+	}
+	if published != "" {
+		head.With(property("article:published_time", published))
+	}
+
+	data := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    kind,
+		"name":     name,
+		"author": map[string]string{
+			"@type": "Person",
+			"name":  author,
+		},
+	}
+	if description != "" {
+		data["description"] = description
+	}
+	switch kind {
+	case "Course":
+		data["provider"] = data["author"]
+	default:
+		data["headline"] = name
+	}
+	if me.baseURL != "" {
+		data["url"] = me.absolute(filename)
+	}
+	if image != "" {
+		data["image"] = image
+	}
+	if published != "" {
+		data["datePublished"] = published
+	}
+	ld, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	return head.With(Script(Type("application/ld+json"), string(ld)))
+}
+
+// absolute returns href relative the base url, empty without one.
+func (me *Website) absolute(href string) string {
+	if me.baseURL == "" || href == "" {
+		return ""
+	}
+	if isRemote(href) {
+		return href
+	}
+	return me.baseURL + "/" + strings.TrimPrefix(href, "/")
+}
+
+// summary returns text of the first non empty paragraph, shortened to
+// fit search results.
+func summary(article *Element) string {
+	for _, p := range Query(article, "p") {
+		text := unescape(plainText(innerText(p)))
+		if text == "" {
+			continue
+		}
+		const max = 160 // characters
+		runes := []rune(text)
+		if len(runes) <= max {
+			return text
+		}
+		text = string(runes[:max])
+		if cut := strings.LastIndex(text, " "); cut != -1 {
+			text = text[:cut]
+		}
+		return strings.TrimRight(text, ",.;:") + "..."
+	}
+	return ""
+}
+
+// innerText returns text of e and its children, unlike Element.Text
+// no space is added between them, e.g. before punctuation following
+// an inline element.
+func innerText(e *Element) string {
+	var buf strings.Builder
+	for _, c := range e.Children {
+		switch c := c.(type) {
+		case *Element:
+			buf.WriteString(innerText(c))
+		default:
+			fmt.Fprint(&buf, c)
+		}
+	}
+	return buf.String()
+}
+
+// fromRoot returns href of the page filename relative the site root.
+func fromRoot(filename, href string) string {
+	if href == "" || isRemote(href) || strings.HasPrefix(href, "/") {
+		return href
+	}
+	return path.Join(path.Dir(filename), href)
+}
+
+// firstAttr returns value of the attribute of the first named element
+// having it.
+func firstAttr(root *Element, name, attr string) string {
+	for _, e := range Query(root, name) {
+		if v := e.AttrVal(attr); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func property(name, content string) *Element {
+	return Meta(&Attribute{Name: "property", Val: name}, Content(escape(content)))
+}
+
+// unescape returns text with entities, e.g. &amp;, resolved.
+func unescape(text string) string {
+	return html.UnescapeString(text)
+}
+
+// escape returns text safe for attribute values.
+func escape(text string) string {
+	return html.EscapeString(text)
+}
@@ -0,0 +1,90 @@
+package website
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_metadata(t *testing.T) {
+	site := &Website{title: "Site", author: "A &amp; B"}
+	WithBaseURL("https://example.com/")(site)
+	article := Article(
+		H1("Title"),
+		P(""),
+		P(`First "paragraph" `, Em("with"), " markup."),
+		Img(Src("img/a.png")),
+		NewElement("time", &Attribute{Name: "datetime", Val: "2021-02-03"}),
+	)
+	head := site.metadata("Article", "Title", "title.html", article)
+
+	assert := asserter.New(t)
+	assert().Equals(meta(head, "name", "description"), `First "paragraph" with markup.`)
+	assert().Equals(meta(head, "name", "author"), "A & B")
+	assert().Equals(meta(head, "property", "og:image"), "https://example.com/img/a.png")
+	assert().Equals(meta(head, "property", "article:published_time"), "2021-02-03")
+	assert().Equals(meta(head, "name", "twitter:card"), "summary_large_image")
+	assert().Equals(
+		MustQueryOne(head, "link").AttrVal("href"), "https://example.com/title.html",
+	)
+	ld := MustQueryOne(head, "script").Text()
+	assert().Contains(ld, `"@type":"Article"`)
+	assert().Contains(ld, `"headline":"Title"`)
+}
+
+func Test_metadata_withoutImage(t *testing.T) {
+	site := &Website{title: "Site"}
+	WithBaseURL("https://example.com")(site)
+	head := site.metadata("Article", "Title", "title.html", Article(H1("Title")))
+
+	assert := asserter.New(t)
+	assert().Equals(meta(head, "property", "og:image"), "")
+	assert().Equals(meta(head, "name", "twitter:image"), "")
+	assert().Equals(meta(head, "name", "twitter:card"), "summary")
+	assert(!strings.Contains(MustQueryOne(head, "script").Text(), `"image"`)).Error(
+		"structured data with image",
+	)
+}
+
+func Test_metadata_imageInSubdirectory(t *testing.T) {
+	site := &Website{title: "Site"}
+	WithBaseURL("https://example.com")(site)
+	article := Article(H1("Title"), Img(Src("../img/a.png")))
+	head := site.metadata("Article", "Title", "drill/title.html", article)
+
+	assert := asserter.New(t)
+	assert().Equals(meta(head, "property", "og:image"), "https://example.com/img/a.png")
+}
+
+func Test_summary(t *testing.T) {
+	assert := asserter.New(t)
+	got := summary(Article(P(strings.Repeat("word ", 50))))
+	assert(len(got) <= 163).Errorf("too long %q", got)
+	assert(strings.HasSuffix(got, "...")).Errorf("not shortened %q", got)
+
+	got = summary(Article(P(strings.Repeat("å", 200))))
+	assert(utf8.ValidString(got)).Errorf("invalid utf8 %q", got)
+	assert().Equals(got, strings.Repeat("å", 160)+"...")
+}
+
+func Test_summary_inlineMarkup(t *testing.T) {
+	got := summary(Article(
+		P("Read ", A(Href("#x"), "Organizing Go Code"), ", by ", Em("someone"), ".\n"),
+	))
+	assert := asserter.New(t)
+	assert().Equals(got, "Read Organizing Go Code, by someone.")
+}
+
+// meta returns content of the meta element in head with the named
+// attribute value.
+func meta(head *Element, attr, name string) string {
+	for _, e := range Query(head, "meta") {
+		if e.AttrVal(attr) == name {
+			return unescape(e.AttrVal("content"))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package website
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// NewPlayground returns a playground using the given base url of a
+// self hosted playground.
+func NewPlayground(base string) *Playground {
+	return &Playground{
+		Base: strings.TrimSuffix(base, "/"),
+	}
+}
+
+// Playground generates run links to a Go Playground compatible
+// service. The program is encoded in the link itself, so links are
+// generated offline and work without uploading anything. The
+// playground must import programs from the z query parameter, see
+// Decode. The official Go Playground does not.
+type Playground struct {
+	Base string
+}
+
+// Link returns the url importing src into the playground.
+func (me *Playground) Link(src []byte) string {
+	return me.Base + "/?" + url.Values{"z": {Encode(src)}}.Encode()
+}
+
+// Import returns the program encoded in a link made by Link.
+func Import(link string) ([]byte, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	z := u.Query().Get("z")
+	if z == "" {
+		return nil, fmt.Errorf("import: no program in %s", link)
+	}
+	return Decode(z)
+}
+
+// Encode returns src compressed with raw deflate, RFC 1951, and
+// base64 url encoded without padding.
+func Encode(src []byte) string {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression) // level is valid
+	w.Write(src)
+	w.Close()
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+// Decode returns the program encoded by Encode.
+func Decode(v string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	src, err := io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,102 @@
+package website
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func TestPlayground(t *testing.T) {
+	srv := httptest.NewServer(newStubPlayground())
+	defer srv.Close()
+
+	pg := NewPlayground(srv.URL + "/")
+	src := drillMain("drill/flag_types.go")
+	link := pg.Link(src)
+	if strings.Contains(link, "package main") {
+		t.Error("program not encoded", link)
+	}
+
+	resp, err := http.Get(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(got, src) {
+		t.Errorf("round trip failed: %s\n%s", resp.Status, got)
+	}
+}
+
+func TestImport(t *testing.T) {
+	src := []byte("package main\n\nfunc main() { println(\"å\") }\n")
+	got, err := Import(NewPlayground("http://example.com").Link(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, src) {
+		t.Errorf("got %q", got)
+	}
+	for _, link := range []string{
+		"http://example.com/",
+		"http://example.com/?z=not+base64!",
+		"http://example.com/?z=" + base64.RawURLEncoding.EncodeToString([]byte("not deflated")),
+	} {
+		if _, err := Import(link); err == nil {
+			t.Errorf("%s: expected error", link)
+		}
+	}
+}
+
+// newStubPlayground returns a handler serving the program imported
+// from the z parameter. It decodes using only the standard library, as
+// a self hosted playground would.
+func newStubPlayground() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("z"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		src, err := io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Write(src)
+	})
+}
+
+func TestWithPlayground(t *testing.T) {
+	assert := asserter.New(t)
+	for _, c := range []struct {
+		base string
+		runs bool
+	}{
+		{"", false},
+		{"https://play.example.com", true},
+	} {
+		site := &Website{
+			refs:    make(map[string]*reference),
+			layouts: defaultLayouts(),
+		}
+		WithPlayground(c.base)(site)
+		site.AddDrill("", "", "drill/flag_names.go")
+		links := Query(site.pages[0].Element, "a")
+		var runs bool
+		for _, a := range links {
+			if strings.HasPrefix(a.AttrVal("href"), c.base+"/?z=") {
+				runs = true
+			}
+		}
+		assert(runs == c.runs).Errorf("base %q: run link %v", c.base, runs)
+	}
+}
@@ -0,0 +1,111 @@
+package spec
+
+import (
+	. "github.com/gregoryv/web"
+)
+
+// NewParticipant returns a participant of dialogs. Color is used to
+// distinguish lines by different participants.
+func NewParticipant(name, role, color string) *Participant {
+	return &Participant{
+		Name:  name,
+		Role:  role,
+		Color: color,
+	}
+}
+
+type Participant struct {
+	Name  string
+	Role  string
+	Color string
+
+	// Avatar is an optional image src shown next to each line
+	Avatar string
+}
+
+// NewDialog returns an empty dialog between the given participants.
+func NewDialog(participants ...*Participant) *Dialog {
+	return &Dialog{
+		participants: participants,
+	}
+}
+
+// Dialog is a turn by turn conversation, e.g. between stakeholders
+// and engineers.
+type Dialog struct {
+	participants []*Participant
+	lines        []*Line
+}
+
+// Say adds a line spoken by the given participant.
+func (me *Dialog) Say(p *Participant, text ...interface{}) *Line {
+	line := &Line{
+		Participant: p,
+		Text:        text,
+	}
+	me.lines = append(me.lines, line)
+	return line
+}
+
+// Element returns the dialog with participants followed by all lines.
+func (me *Dialog) Element() *Element {
+	ul := Ul(Class("participants"))
+	for _, p := range me.participants {
+		ul.With(Li(
+			Span(Class("speaker"), styleColor(p.Color), p.Name),
+			" &ndash; ", p.Role,
+		))
+	}
+	div := Div(Class("dialog"), ul)
+	for _, line := range me.lines {
+		div.With(line.Element())
+	}
+	return div
+}
+
+// Line is spoken by one participant in a dialog.
+type Line struct {
+	Participant *Participant
+	Text        []interface{}
+	Annotations []*Annotation
+}
+
+// Elicit annotates the line with a requirement it elicits.
+func (me *Line) Elicit(id, explanation string) *Line {
+	me.Annotations = append(me.Annotations, &Annotation{
+		Requirement: id,
+		Text:        explanation,
+	})
+	return me
+}
+
+func (me *Line) Element() *Element {
+	p := me.Participant
+	div := Div(Class("line"), styleBorder(p.Color))
+	if p.Avatar != "" {
+		div.With(Img(Class("avatar"), Src(p.Avatar), Alt(p.Name)))
+	}
+	div.With(
+		Span(Class("speaker"), styleColor(p.Color), "&#8213; ", p.Name, ": "),
+	).With(me.Text...)
+	for _, a := range me.Annotations {
+		div.With(Div(Class("elicits"),
+			"Elicits ", A(Href("#"+a.Requirement), a.Requirement), " ", a.Text,
+		))
+	}
+	return div
+}
+
+// Annotation explains which requirement a line elicits.
+type Annotation struct {
+	Requirement string
+	Text        string
+}
+
+func styleColor(v string) *Attribute {
+	return Attr("style", "color: "+v)
+}
+
+func styleBorder(v string) *Attribute {
+	return Attr("style", "border-left-color: "+v)
+}
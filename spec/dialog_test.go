@@ -0,0 +1,44 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func TestDialog(t *testing.T) {
+	var (
+		a = NewParticipant("Ann", "customer", "red")
+		b = NewParticipant("Bo", "engineer", "blue")
+		d = NewDialog(a, b)
+	)
+	b.Avatar = "bo.png"
+	d.Say(a, "Hello")
+	d.Say(b, "Hi, ", "what's up?").Elicit("R9", "greeting")
+
+	dialog := d.Element()
+	assert := asserter.New(t)
+	participants := Query(dialog, "li")
+	assert().Equals(len(participants), 2)
+	assert().Equals(Query(participants[0], "span")[0].Text(), "Ann")
+	assert().Equals(participants[1].Children[2], "engineer")
+
+	lines := Query(dialog, "div.line")
+	assert().Equals(len(lines), 2)
+	assert().Equals(lines[0].AttrVal("style"), "border-left-color: red")
+	assert().Equals(Query(lines[0], "span")[0].Children, []interface{}{"&#8213; ", "Ann", ": "})
+	assert().Equals(lines[0].Children[1], "Hello")
+	assert().Equals(len(Query(lines[0], "img")), 0)
+	assert().Equals(len(Query(lines[0], "div.elicits")), 0)
+
+	avatar := Query(lines[1], "img")
+	assert(len(avatar) == 1).Fatal("missing avatar")
+	assert().Equals(avatar[0].AttrVal("src"), "bo.png")
+	assert().Equals(avatar[0].AttrVal("alt"), "Bo")
+	assert().Equals(Query(lines[1], "span")[0].AttrVal("style"), "color: blue")
+
+	elicits := Query(lines[1], "div.elicits")
+	assert(len(elicits) == 1).Fatal("missing annotation")
+	assert().Equals(Query(elicits[0], "a")[0].AttrVal("href"), "#R9")
+}
@@ -0,0 +1,259 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
+)
+
+// NewPrintableSpecification returns the specification as one self
+// contained page, ready for printing. Images are read relative to dir.
+func NewPrintableSpecification(dir string) (*Page, error) {
+	page := NewSpecification()
+	err := Inline(page.Element, dir, tokens.Default)
+	return page, err
+}
+
+// Inline makes root self contained by replacing stylesheet links and
+// relative image sources with their content, read relative to dir.
+// Remote imports are removed and page break rules for printing, with
+// colors of the light palette of t, are added to the head.
+func Inline(root *Element, dir string, t tokens.Tokens) error {
+	if err := inline(root, dir); err != nil {
+		return err
+	}
+	for _, head := range Query(root, "head") {
+		head.With(Style(printRules(t.Light)))
+	}
+	return nil
+}
+
+func inline(e *Element, dir string) error {
+	for i, c := range e.Children {
+		c, ok := c.(*Element)
+		if !ok {
+			continue
+		}
+		switch {
+		case c.Name == "link" && c.AttrVal("rel") == "stylesheet":
+			data, err := os.ReadFile(filepath.Join(dir, c.AttrVal("href")))
+			if err != nil {
+				return err
+			}
+			e.Children[i] = Style(stripRemoteImports(string(data)))
+
+		case c.Name == "style":
+			css := Wrap(c.Children...).String()
+			c.Children = []interface{}{stripRemoteImports(css)}
+
+		case c.Name == "img" && isLocal(c.AttrVal("src")):
+			src := c.Attr("src")
+			uri, err := dataURI(filepath.Join(dir, src.Val))
+			if err != nil {
+				return err
+			}
+			src.Val = uri
+		}
+		if err := inline(c, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isLocal(src string) bool {
+	return src != "" && !strings.Contains(src, "://") &&
+		!strings.HasPrefix(src, "data:")
+}
+
+// dataURI returns the file content as a base64 encoded data uri.
+func dataURI(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	typ := mime.TypeByExtension(filepath.Ext(filename))
+	if typ == "" {
+		typ = "application/octet-stream"
+	}
+	return fmt.Sprintf("data:%s;base64,%s",
+		typ, base64.StdEncoding.EncodeToString(data),
+	), nil
+}
+
+var remoteImport = regexp.MustCompile(`(?m)^@import url\('?[a-z]+://[^)]*\);\n?`)
+
+func stripRemoteImports(css string) string {
+	return remoteImport.ReplaceAllString(css, "")
+}
+
+func printRules(p tokens.Palette) *CSS {
+	css := NewCSS()
+	css.Style("@page",
+		"size: A4",
+		"margin: 2cm",
+	)
+	print := css.Media("print")
+	print.Style("body",
+		"padding: 0",
+		"max-width: none",
+	)
+	print.Style("h1, h2, h3, h4",
+		"page-break-after: avoid",
+	)
+	print.Style("article > article",
+		"page-break-before: always",
+	)
+	print.Style(".requirement, .dialog .line, table, pre",
+		"page-break-inside: avoid",
+	)
+	print.Style("a",
+		"color: "+p.Ink,
+		"text-decoration: none",
+	)
+	return css
+}
+
+// ----------------------------------------
+
+// WriteMarkdown writes the element tree as markdown, e.g. for
+// reviewing the specification in pull requests.
+func WriteMarkdown(w io.Writer, root *Element) error {
+	var buf bytes.Buffer
+	writeBlocks(&buf, root)
+	md := manyNewlines.ReplaceAll(buf.Bytes(), []byte("\n\n"))
+	_, err := w.Write(append(bytes.TrimSpace(md), '\n'))
+	return err
+}
+
+var manyNewlines = regexp.MustCompile(`\n{3,}`)
+
+// writeBlocks writes children of e, grouping consecutive inline
+// children into paragraphs.
+func writeBlocks(w *bytes.Buffer, e *Element) {
+	var para []interface{}
+	flush := func() {
+		if txt := strings.TrimSpace(inlineText(para)); txt != "" {
+			w.WriteString(txt + "\n\n")
+		}
+		para = nil
+	}
+	for _, c := range e.Children {
+		el, ok := c.(*Element)
+		if !ok || !isBlock(el.Name) {
+			para = append(para, c)
+			continue
+		}
+		flush()
+		writeBlock(w, el)
+	}
+	flush()
+}
+
+func writeBlock(w *bytes.Buffer, e *Element) {
+	switch e.Name {
+	case "head", "style", "script", "nav":
+
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(e.Name[1] - '0')
+		w.WriteString(strings.Repeat("#", level) + " ")
+		w.WriteString(strings.TrimSpace(inlineText(e.Children)) + "\n\n")
+
+	case "p":
+		w.WriteString(strings.TrimSpace(inlineText(e.Children)) + "\n\n")
+
+	case "ul", "ol":
+		for i, li := range Query(e, "li") {
+			prefix := "- "
+			if e.Name == "ol" {
+				prefix = fmt.Sprintf("%d. ", i+1)
+			}
+			w.WriteString(prefix + strings.TrimSpace(inlineText(li.Children)) + "\n")
+		}
+		w.WriteString("\n")
+
+	case "pre":
+		w.WriteString("```\n" + strings.TrimRight(e.Text(), "\n") + "\n```\n\n")
+
+	case "table":
+		for i, tr := range Query(e, "tr") {
+			cells := make([]string, 0)
+			for _, c := range tr.Children {
+				if c, ok := c.(*Element); ok {
+					cells = append(cells, strings.TrimSpace(inlineText(c.Children)))
+				}
+			}
+			w.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+			if i == 0 {
+				w.WriteString(strings.Repeat("|---", len(cells)) + "|\n")
+			}
+		}
+		w.WriteString("\n")
+
+	default:
+		writeBlocks(w, e)
+	}
+}
+
+func isBlock(name string) bool {
+	switch name {
+	case "html", "head", "body", "article", "section", "div", "nav",
+		"header", "footer", "wrapper", "style", "script",
+		"h1", "h2", "h3", "h4", "h5", "h6",
+		"p", "ul", "ol", "pre", "table", "blockquote":
+		return true
+	}
+	return false
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+func inlineText(children []interface{}) string {
+	var buf strings.Builder
+	for _, c := range children {
+		switch c := c.(type) {
+		case *Element:
+			switch c.Name {
+			case "code":
+				buf.WriteString("`" + c.Text() + "`")
+			case "em", "i":
+				buf.WriteString(emphasis("*", inlineText(c.Children)))
+			case "b", "strong":
+				buf.WriteString(emphasis("**", inlineText(c.Children)))
+			case "a":
+				buf.WriteString("[" + inlineText(c.Children) + "](" + c.AttrVal("href") + ")")
+			case "img":
+				buf.WriteString("![" + c.AttrVal("alt") + "](" + c.AttrVal("src") + ")")
+			case "br":
+				buf.WriteString("  \n")
+			case "ul", "ol", "div", "p":
+				// nested blocks, e.g. annotations, continue on a new line
+				buf.WriteString("  \n" + inlineText(c.Children))
+			default:
+				buf.WriteString(inlineText(c.Children))
+			}
+		default:
+			buf.WriteString(whitespace.ReplaceAllString(fmt.Sprint(c), " "))
+		}
+	}
+	return buf.String()
+}
+
+// emphasis wraps the text in marks, keeping surrounding spaces outside.
+func emphasis(mark, text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	i := strings.Index(text, trimmed)
+	return text[:i] + mark + trimmed + mark + text[i+len(trimmed):]
+}
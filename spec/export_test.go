@@ -0,0 +1,60 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
+)
+
+func TestInline(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.css"), []byte("p { color: red; }"), 0644)
+	os.WriteFile(filepath.Join(dir, "x.png"), []byte("png"), 0644)
+
+	tk := tokens.Default
+	tk.Light.Ink = "#010203"
+	tk.Light.Border = "#040506"
+	page := Html(
+		Head(
+			Link(Rel("stylesheet"), Href("a.css")),
+			Style(theme(tk)),
+		),
+		Body(Img(Src("x.png")), Img(Src("https://example.com/y.png"))),
+	)
+	ok, bad := asserter.NewErrors(t)
+	ok(Inline(page, dir, tk))
+
+	assert := asserter.New(t)
+	styles := Query(page, "style")
+	assert().Equals(len(styles), 3) // inlined link, theme and print rules
+	assert().Equals(strings.TrimSpace(styles[0].String()), "<style>p { color: red; }</style>")
+	assert().Contains(styles[1].String(), "border: 1px solid #040506")
+	assert().Contains(styles[2].String(), "color: #010203")
+	assert(!strings.Contains(styles[1].String(), "@import")).Error("remote import kept")
+
+	imgs := Query(page, "img")
+	assert().Equals(imgs[0].AttrVal("src"), "data:image/png;base64,cG5n")
+	assert().Equals(imgs[1].AttrVal("src"), "https://example.com/y.png")
+
+	missing := Html(Head(Link(Rel("stylesheet"), Href("missing.css"))))
+	bad(Inline(missing, dir, tk))
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf strings.Builder
+	WriteMarkdown(&buf, Article(
+		H1("Title"),
+		P("Some <b>raw</b> ", Code("code"), " and ", A(Href("#x"), "link")),
+		Ul(Li("one"), Li(Em("two"))),
+		Table(Tr(Th("a"), Th("b")), Tr(Td("1"), Td("2"))),
+	))
+	exp := "# Title\n\nSome <b>raw</b> `code` and [link](#x)\n\n- one\n- *two*\n\n| a | b |\n|---|---|\n| 1 | 2 |\n"
+	if got := buf.String(); got != exp {
+		t.Errorf("got\n%q\nexpected\n%q", got, exp)
+	}
+}
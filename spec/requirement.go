@@ -0,0 +1,203 @@
+package spec
+
+import (
+	"fmt"
+
+	. "github.com/gregoryv/web"
+)
+
+// NewStory returns a story with a stable id, used for referencing
+// it from features.
+func NewStory(id, title string, content ...interface{}) *Story {
+	return &Story{
+		ID:      id,
+		Title:   title,
+		Content: content,
+	}
+}
+
+// Story is a narrative from which features are elicited.
+type Story struct {
+	ID      string
+	Title   string
+	Content []interface{}
+}
+
+func (me *Story) Element(n *Hn) *Element {
+	return Section(Id(me.ID), n.H2(me.Title)).With(me.Content...)
+}
+
+// NewFeature returns a feature with a stable id.
+func NewFeature(id, title string) *Feature {
+	return &Feature{
+		ID:    id,
+		Title: title,
+	}
+}
+
+// Feature groups requirements and links them to the stories they
+// were elicited from.
+type Feature struct {
+	ID           string
+	Title        string
+	Description  string
+	Stories      []*Story
+	Requirements []*Requirement
+}
+
+// ElicitedFrom links the feature to the given stories.
+func (me *Feature) ElicitedFrom(v ...*Story) *Feature {
+	me.Stories = append(me.Stories, v...)
+	return me
+}
+
+// Require adds requirements to the feature.
+func (me *Feature) Require(v ...*Requirement) *Feature {
+	me.Requirements = append(me.Requirements, v...)
+	return me
+}
+
+func (me *Feature) Element(n *Hn) *Element {
+	s := Section(Id(me.ID),
+		n.H2(me.Title, " ", Code(me.ID)),
+	)
+	if me.Description != "" {
+		s.With(P(me.Description))
+	}
+	if len(me.Stories) > 0 {
+		from := P("Elicited from ")
+		for i, story := range me.Stories {
+			if i > 0 {
+				from.With(", ")
+			}
+			from.With(A(Href("#"+story.ID), story.Title))
+		}
+		s.With(from)
+	}
+	for _, r := range me.Requirements {
+		s.With(r.Element())
+	}
+	return s
+}
+
+// NewRequirement returns a requirement with a stable id and text
+// describing what is required.
+func NewRequirement(id, text string) *Requirement {
+	return &Requirement{
+		ID:   id,
+		Text: text,
+	}
+}
+
+type Requirement struct {
+	ID         string
+	Text       string
+	Rationale  string
+	Acceptance []string
+}
+
+// Because sets the rationale of the requirement.
+func (me *Requirement) Because(v string) *Requirement {
+	me.Rationale = v
+	return me
+}
+
+// AcceptWhen adds acceptance criteria.
+func (me *Requirement) AcceptWhen(v ...string) *Requirement {
+	me.Acceptance = append(me.Acceptance, v...)
+	return me
+}
+
+func (me *Requirement) Element() *Element {
+	div := Div(Class("requirement"), Id(me.ID),
+		P(Code(me.ID), " ", me.Text),
+	)
+	if me.Rationale != "" {
+		div.With(P(Em("Rationale: "), me.Rationale))
+	}
+	if len(me.Acceptance) > 0 {
+		ul := Ul()
+		for _, c := range me.Acceptance {
+			ul.With(Li(c))
+		}
+		div.With(P(Em("Acceptance criteria")), ul)
+	}
+	return div
+}
+
+// NewTraceabilityMatrix returns a table with one row per requirement,
+// marking the stories it can be traced to. Panics on duplicate ids.
+func NewTraceabilityMatrix(features ...*Feature) *Element {
+	mustBeUnique(features)
+	stories := storiesOf(features)
+
+	head := Tr(Th("Requirement"), Th("Feature"))
+	for _, s := range stories {
+		head.With(Th(A(Href("#"+s.ID), s.ID)))
+	}
+	tbody := Tbody()
+	for _, f := range features {
+		for _, r := range f.Requirements {
+			row := Tr(
+				Td(A(Href("#"+r.ID), r.ID)),
+				Td(A(Href("#"+f.ID), f.ID)),
+			)
+			for _, s := range stories {
+				row.With(Td(mark(f.tracesTo(s))))
+			}
+			tbody.With(row)
+		}
+	}
+	return Table(Class("traceability"), Thead(head), tbody)
+}
+
+func (me *Feature) tracesTo(s *Story) bool {
+	for _, v := range me.Stories {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// storiesOf returns all stories linked from features, in order of
+// appearance.
+func storiesOf(features []*Feature) []*Story {
+	res := make([]*Story, 0)
+	seen := make(map[*Story]bool)
+	for _, f := range features {
+		for _, s := range f.Stories {
+			if !seen[s] {
+				seen[s] = true
+				res = append(res, s)
+			}
+		}
+	}
+	return res
+}
+
+func mustBeUnique(features []*Feature) {
+	ids := make(map[string]bool)
+	check := func(id string) {
+		if ids[id] {
+			panic(fmt.Sprintf("duplicate id %q", id))
+		}
+		ids[id] = true
+	}
+	for _, s := range storiesOf(features) {
+		check(s.ID)
+	}
+	for _, f := range features {
+		check(f.ID)
+		for _, r := range f.Requirements {
+			check(r.ID)
+		}
+	}
+}
+
+func mark(v bool) string {
+	if v {
+		return "&#10003;"
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func TestNewTraceabilityMatrix(t *testing.T) {
+	var (
+		s1 = NewStory("S1", "one")
+		s2 = NewStory("S2", "two")
+		f1 = NewFeature("F1", "first").ElicitedFrom(s1).Require(
+			NewRequirement("R1", "must"),
+		)
+		f2 = NewFeature("F2", "second").ElicitedFrom(s1, s2).Require(
+			NewRequirement("R2", "should"),
+			NewRequirement("R3", "may"),
+		)
+	)
+	matrix := NewTraceabilityMatrix(f1, f2)
+	assert := asserter.New(t)
+	assert().Equals(cells(matrix), [][]string{
+		{"Requirement", "Feature", "S1", "S2"},
+		{"R1", "F1", "&#10003;", ""},
+		{"R2", "F2", "&#10003;", "&#10003;"},
+		{"R3", "F2", "&#10003;", "&#10003;"},
+	})
+	var hrefs []string
+	for _, a := range Query(matrix, "a") {
+		hrefs = append(hrefs, a.AttrVal("href"))
+	}
+	assert().Equals(hrefs, []string{
+		"#S1", "#S2", "#R1", "#F1", "#R2", "#F2", "#R3", "#F2",
+	})
+
+	defer func() {
+		assert(recover() != nil).Error("expected panic on duplicate ids")
+	}()
+	NewTraceabilityMatrix(f1, NewFeature("F1", "again"))
+}
+
+// cells returns text of each cell in the table, row by row.
+func cells(table *Element) [][]string {
+	res := make([][]string, 0)
+	for _, tr := range Query(table, "tr") {
+		row := make([]string, 0)
+		for _, c := range tr.Children {
+			row = append(row, c.(*Element).Text())
+		}
+		res = append(res, row)
+	}
+	return res
+}
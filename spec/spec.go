@@ -2,16 +2,33 @@ package spec
 
 import (
 	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
 )
 
+// NewSpecification returns the specification as a standalone page.
 func NewSpecification() *Page {
-	return newPage(
+	return NewSpecificationPage(tokens.Default)
+}
+
+// NewSpecificationPage returns the specification as a standalone page
+// styled with the given tokens. Optional themes, e.g. with @font-face
+// rules, are added after the specification theme.
+func NewSpecificationPage(t tokens.Tokens, themes ...*CSS) *Page {
+	return newPage(t, themes, NewSpecificationArticle())
+}
+
+// NewSpecificationArticle returns the specification for including in
+// other pages, styled by Theme.
+func NewSpecificationArticle() *Element {
+	return Article(Class("spec"),
 		H1("Exploring requirements engineering"),
 
 		Article(
 			NewExploreRequirementsEngineering(),
 			NewBeachStory(),
 		),
+		NewElicitedFeatures(NewHn(2)),
+		NewNavigationSystemSpec(NewHn(2)),
 	)
 }
 
@@ -52,18 +69,32 @@ func NewExploreRequirementsEngineering() *Element {
 		tech lead. They talk to engineers responsible for the
 		software in our story it's Jane.`),
 
-		John(`Hello, Jane! ready to start working on the control
-		system?`),
-
-		Jane(`Good morning, John! ready as can be, let's sit down.`),
+		newControlSystemDialog().Element(),
 		//
 	)
 }
 
+var (
+	john = NewParticipant("John", "tech lead, space ship department", "#4f7fbf")
+	jane = NewParticipant("Jane", "software engineer", "#bf6f4f")
+)
+
+func newControlSystemDialog() *Dialog {
+	d := NewDialog(john, jane)
+	d.Say(john, `Hello, Jane! ready to start working on the control
+	system?`)
+	d.Say(jane, `Good morning, John! ready as can be, let's sit down.`)
+	return d
+}
+
 func NewBeachStory() *Element {
-	return Section(
-		H2("To the beach"),
+	return newBeachStory().Element(NewHn(1))
+}
 
+// newBeachStory returns the story with new content elements, as
+// they are changed when included in a page, e.g. by heading anchors.
+func newBeachStory() *Story {
+	return NewStory("S1", "To the beach",
 		P(`Through the navigation system people can plot a course or
 		manually steer a ship.  People depend on its accuracy and
 		automation to safely navigate through space.`),
@@ -86,24 +117,54 @@ func NewBeachStory() *Element {
 		H3(`Manual control`),
 
 		P(`Once the ship enters the planets atmosphere one of the crew
-	 	members on the bridge tells the system to let him manually
-	 	steer the ship. He wants to find a suitable spot on the
-	 	crowded beach, before letting the passengers leave the
-	 	ship.`),
-		//
+		members on the bridge tells the system to let him manually
+		steer the ship. He wants to find a suitable spot on the
+		crowded beach, before letting the passengers leave the
+		ship.`),
 	)
 }
 
+func newNavigationFeatures() []*Feature {
+	beachStory := newBeachStory()
+	return []*Feature{
+		NewFeature("F1", "Voice control").ElicitedFrom(beachStory).Require(
+			NewRequirement("R1", `The system shall accept spoken commands
+			from people on the bridge.`).
+				Because(`The captain tells the system to plot and engage.`).
+				AcceptWhen(`A spoken destination results in a plotted course`),
+		),
+		NewFeature("F2", "Show route details").ElicitedFrom(beachStory).Require(
+			NewRequirement("R2", `Plotted routes shall show estimated travel
+			time and if they pass through uncharted space.`).
+				Because(`The captain avoids routes through uncharted space.`),
+			NewRequirement("R3", `Course changes shall be notified to the
+			captain.`).
+				Because(`The ship adapts its course on interference.`).
+				AcceptWhen(`A notification reaches the personal communicator`),
+		),
+		NewFeature("F3", "Find destination").ElicitedFrom(beachStory).Require(
+			NewRequirement("R4", `The system shall list viable destinations,
+			closest first.`).
+				Because(`The captain asks for the closest viable planets.`),
+		),
+		NewFeature("F4", "Manual control").ElicitedFrom(beachStory).Require(
+			NewRequirement("R5", `Crew members on the bridge shall be able to
+			take manual control of the ship.`).
+				Because(`Finding a spot on a crowded beach needs a human touch.`),
+		),
+	}
+}
+
 func NewElicitedFeatures(n *Hn) *Element {
-	return Article(
+	a := Article(
 		n.H1("Elicited features"),
 
 		P(`These features have been elicitated from the navigation story`),
-
-		n.H2("voice control"),
-		n.H2("show route details"),
-		n.H2("find destination"),
 	)
+	for _, f := range newNavigationFeatures() {
+		a.With(f.Element(n))
+	}
+	return a
 }
 
 func NewNavigationSystemSpec(n *Hn) *Element {
@@ -117,26 +178,38 @@ func NewNavigationSystemSpec(n *Hn) *Element {
         manually steer a ship.  People depend on its accuracy and
         automation to safely navigate through space.`),
 
+		n.H2("Traceability"),
+
+		NewTraceabilityMatrix(newNavigationFeatures()...),
 		//
 	)
 }
 
+// John returns a line spoken by John.
+//
+// Deprecated: use NewDialog and Dialog.Say.
 func John(el ...interface{}) *Element {
 	return Div("&#8213; John: ").With(el...)
 }
 
+// Jane returns a line spoken by Jane.
+//
+// Deprecated: use NewDialog and Dialog.Say.
 func Jane(el ...interface{}) *Element {
 	return Div("&#8213; Jane: ").With(el...)
 }
 
-func newPage(content ...interface{}) *Page {
-
+func newPage(t tokens.Tokens, themes []*CSS, content ...interface{}) *Page {
+	css := theme(t)
+	for _, theme := range themes {
+		css.With(theme)
+	}
 	return NewPage(
 
 		Html(
 			Head(
 				Meta(Charset("utf-8")),
-				Style(theme()),
+				Style(css),
 				Script(
 					// to prevent Firefox FOUC, this must be here
 					// https://stackoverflow.com/questions/21147149
@@ -1,7 +1,11 @@
 package spec
 
 import (
+	"os"
 	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
 )
 
 func Test_index(t *testing.T) {
@@ -10,3 +14,35 @@ func Test_index(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func Test_printable(t *testing.T) {
+	page, err := NewPrintableSpecification("docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := page.SaveAs("docs/print.html"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_markdown(t *testing.T) {
+	fh, err := os.Create("docs/spec.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	if err := WriteMarkdown(fh, NewSpecification().Element); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewSpecificationArticle(t *testing.T) {
+	exp := NewSpecificationArticle().String()
+	// including pages change elements, e.g. by anchoring headings
+	a := NewSpecificationArticle()
+	for _, h3 := range Query(a, "h3") {
+		h3.With(Id("changed"))
+	}
+	assert := asserter.New(t)
+	assert().Equals(NewSpecificationArticle().String(), exp)
+}
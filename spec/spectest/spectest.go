@@ -0,0 +1,23 @@
+// Package spectest links tests to the requirements they verify, see
+// spec.Verification.
+package spectest
+
+import (
+	"strings"
+
+	"github.com/sogvin/website/spec"
+)
+
+// Verifies declares that the calling test verifies the given
+// requirement ids. The declaration is logged and picked up from go
+// test -json output, see spec.Verification.ReadTestJSON.
+func Verifies(t T, ids ...string) {
+	t.Helper()
+	t.Log(spec.VerifiesTag, strings.Join(ids, " "))
+}
+
+// T is implemented by testing.TB.
+type T interface {
+	Helper()
+	Log(args ...interface{})
+}
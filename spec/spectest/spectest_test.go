@@ -0,0 +1,47 @@
+package spectest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/sogvin/website/spec"
+)
+
+func TestVerifies(t *testing.T) {
+	rec := &recorder{}
+	Verifies(rec, "R1", "R2")
+	assert := asserter.New(t)
+	assert(rec.helper).Error("Helper not called")
+
+	// as printed by go test -json
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	enc.Encode(map[string]string{
+		"Action": "output", "Package": "example.com/nav", "Test": "TestPlot",
+		"Output": "    nav_test.go:9: " + rec.logged,
+	})
+	enc.Encode(map[string]string{
+		"Action": "pass", "Package": "example.com/nav", "Test": "TestPlot",
+	})
+	v := spec.NewVerification()
+	if err := v.ReadTestJSON(strings.NewReader(buf.String())); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"R1", "R2"} {
+		assert().Equals(v.Status(id), spec.Verified)
+		assert().Equals(strings.Join(v.Tests(id), " "), "nav.TestPlot")
+	}
+	assert().Equals(v.Status("R3"), spec.Uncovered)
+}
+
+type recorder struct {
+	helper bool
+	logged string
+}
+
+func (me *recorder) Helper() { me.helper = true }
+
+func (me *recorder) Log(args ...interface{}) { me.logged += fmt.Sprintln(args...) }
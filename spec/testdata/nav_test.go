@@ -0,0 +1,8 @@
+package nav
+
+import "testing"
+
+// verifies: R1 R2
+func TestPlot(t *testing.T) {}
+
+func TestOther(t *testing.T) {}
@@ -1,18 +1,27 @@
 package spec
 
-import . "github.com/gregoryv/web"
+import (
+	"fmt"
 
-func theme() *CSS {
-	css := NewCSS()
-	css.Import("https://fonts.googleapis.com/css?family=Inconsolata|Source+Sans+Pro")
+	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
+)
 
+func theme(t tokens.Tokens) *CSS {
+	css := NewCSS()
+	if t.FontImport != "" {
+		css.Import(t.FontImport)
+	}
 	css.Style("html, body",
 		"margin: 0 0",
 		"padding: 0 0",
-		"font-family: 'Source Sans Pro', sans-serif",
+		"font-family: "+t.Font,
+	)
+	css.Style("code",
+		"font-family: "+t.CodeFont,
 	)
 	css.Style("body",
-		"padding: 1em 1.618em 1em 1.618em",
+		fmt.Sprintf("padding: 1em %s 1em %s", t.Space.Large, t.Space.Large),
 		"max-width: 21cm",
 		"line-height: 1.3em",
 	)
@@ -27,11 +36,66 @@ func theme() *CSS {
 		"padding-left: 0",
 	)
 	css.Style("li.h3",
-		"margin-left: 1.618em",
+		"margin-left: "+t.Space.Large,
 	)
 	css.Style("li.h4",
 		"margin-left: 3.236em",
 	)
+	return css.With(Theme(t))
+}
+
+// Theme returns styles of specification elements, e.g. requirements,
+// dialogs and traceability matrix, using the light palette of t.
+func Theme(t tokens.Tokens) *CSS {
+	c := t.Light
+	css := NewCSS()
+	css.Filename = "spec.css"
+
+	css.Style("table.traceability",
+		"border-collapse: collapse",
+	)
+	css.Style("table.traceability th, table.traceability td",
+		"border: 1px solid "+c.Border,
+		"padding: 0 "+t.Space.Medium,
+		"text-align: center",
+	)
+	css.Style(".requirement p",
+		"margin: "+t.Space.Small+" 0",
+	)
+	css.Style(".dialog ul.participants",
+		"list-style-type: none",
+		"padding-left: 0",
+	)
+	css.Style(".dialog .line",
+		"border-left: 4px solid "+c.Rule,
+		"padding-left: "+t.Space.Medium,
+		"margin-bottom: "+t.Space.Medium,
+	)
+	css.Style(".dialog .speaker",
+		"font-weight: bold",
+	)
+	css.Style(".dialog .avatar",
+		"height: "+t.Space.Large,
+		"vertical-align: middle",
+		"margin-right: "+t.Space.Small,
+	)
+	css.Style(".dialog .elicits",
+		"font-size: 0.8em",
+		"color: "+c.Border,
+	)
+	css.Style(".verified, .failing, .uncovered",
+		"padding: 0 "+t.Space.Small,
+		"color: "+c.OnAccent,
+	)
+	css.Style(".verified",
+		"background-color: "+c.Success,
+	)
+	css.Style(".failing",
+		"background-color: "+c.Failure,
+	)
+	css.Style(".uncovered",
+		"background-color: "+c.Border,
+	)
 
 	return css
 }
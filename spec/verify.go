@@ -0,0 +1,267 @@
+package spec
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// VerifiesTag is used both in test doc comments and test output, see
+// spectest.Verifies.
+const VerifiesTag = "verifies:"
+
+var verifiesLine = regexp.MustCompile(VerifiesTag + `\s*(.*)`)
+
+// NewVerification returns an empty verification where all
+// requirements are uncovered.
+func NewVerification() *Verification {
+	return &Verification{
+		tests:   make(map[string][]testID),
+		failed:  make(map[testID]bool),
+		passed:  make(map[testID]bool),
+		modules: make(map[string]string),
+	}
+}
+
+// Verification links requirement ids to tests and their results.
+type Verification struct {
+	tests  map[string][]testID // requirement id to tests
+	failed map[testID]bool
+	passed map[testID]bool
+
+	modules map[string]string // directory to module path
+}
+
+// testID identifies a test by package import path and name, test
+// names are only unique within a package.
+type testID struct {
+	Package string
+	Test    string
+}
+
+func (me testID) String() string {
+	return path.Base(me.Package) + "." + me.Test
+}
+
+// Cover declares the named test in package pkg, an import path, to
+// verify the given requirements.
+func (me *Verification) Cover(pkg, test string, ids ...string) {
+	for _, id := range ids {
+		me.tests[id] = append(me.tests[id], testID{pkg, test})
+	}
+}
+
+// ScanSources declares coverage for each test function, in test
+// files found below dir, with a doc comment line such as
+//
+//	// verifies: R1 R2
+func (me *Verification) ScanSources(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != dir && ignoredDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		pkg, err := me.importPath(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+				continue
+			}
+			for _, c := range fn.Doc.List {
+				if m := verifiesLine.FindStringSubmatch(c.Text); m != nil {
+					me.Cover(pkg, fn.Name.Name, strings.Fields(m[1])...)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// importPath returns the import path of the package in dir using the
+// module path of the nearest go.mod.
+func (me *Verification) importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		mod, found := me.modules[root]
+		if !found {
+			data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+			if m := modulePath.FindSubmatch(data); m != nil {
+				mod = string(m[1])
+			}
+			me.modules[root] = mod
+		}
+		if mod != "" {
+			rel, _ := filepath.Rel(root, abs)
+			return path.Join(mod, filepath.ToSlash(rel)), nil
+		}
+		if root == filepath.Dir(root) {
+			return "", fmt.Errorf("%s: no go.mod found", dir)
+		}
+	}
+}
+
+var modulePath = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)`)
+
+// ignoredDir returns true for directories ignored by the go tool.
+func ignoredDir(name string) bool {
+	return name == "testdata" || strings.HasPrefix(name, ".") ||
+		strings.HasPrefix(name, "_")
+}
+
+// ReadTestJSON reads go test -json output, recording test results and
+// coverage declared with spectest.Verifies.
+func (me *Verification) ReadTestJSON(r io.Reader) error {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if !strings.HasPrefix(s.Text(), "{") {
+			continue // e.g. build errors on stderr
+		}
+		var e testEvent
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return err
+		}
+		if e.Test == "" {
+			continue
+		}
+		test := testID{e.Package, e.Test}
+		switch e.Action {
+		case "pass":
+			me.passed[test] = true
+		case "fail":
+			me.failed[test] = true
+		case "output":
+			if m := verifiesLine.FindStringSubmatch(e.Output); m != nil {
+				me.Cover(e.Package, e.Test, strings.Fields(m[1])...)
+			}
+		}
+	}
+	return s.Err()
+}
+
+// testEvent is one line of go test -json output
+type testEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Output  string
+}
+
+// Status returns the verification status of the requirement. A
+// requirement is failing if any of its tests, or their parents, fail.
+func (me *Verification) Status(id string) Status {
+	tests := me.tests[id]
+	var passed bool
+	for _, test := range tests {
+		if me.failed[test] || me.failed[test.root()] {
+			return Failing
+		}
+		if me.passed[test] {
+			passed = true
+		}
+	}
+	if passed {
+		return Verified
+	}
+	return Uncovered
+}
+
+// Tests returns sorted names of tests covering the requirement,
+// prefixed with the package name, e.g. spec.TestVerification.
+func (me *Verification) Tests(id string) []string {
+	res := make([]string, 0, len(me.tests[id]))
+	seen := make(map[testID]bool)
+	for _, test := range me.tests[id] {
+		if !seen[test] {
+			seen[test] = true
+			res = append(res, test.String())
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Mark adds the status to each requirement and traceability matrix
+// found in root.
+func (me *Verification) Mark(root *Element) {
+	for _, r := range Query(root, "div.requirement") {
+		id := r.AttrVal("id")
+		r.With(P(Em("Status: "), me.Status(id).Element(), " ", strings.Join(me.Tests(id), ", ")))
+	}
+	for _, table := range Query(root, "table.traceability") {
+		for i, tr := range Query(table, "tr") {
+			if i == 0 {
+				tr.With(Th("Status"))
+				continue
+			}
+			a := Query(tr, "a")
+			if len(a) == 0 {
+				continue
+			}
+			id := strings.TrimPrefix(a[0].AttrVal("href"), "#")
+			tr.With(Td(me.Status(id).Element()))
+		}
+	}
+}
+
+// root returns the top level test of a subtest.
+func (me testID) root() testID {
+	if i := strings.Index(me.Test, "/"); i > 0 {
+		return testID{me.Package, me.Test[:i]}
+	}
+	return me
+}
+
+type Status int
+
+const (
+	Uncovered Status = iota
+	Verified
+	Failing
+)
+
+func (s Status) String() string {
+	switch s {
+	case Verified:
+		return "verified"
+	case Failing:
+		return "failing"
+	default:
+		return "uncovered"
+	}
+}
+
+func (s Status) Element() *Element {
+	return Span(Class(s.String()), s.String())
+}
@@ -0,0 +1,51 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func TestVerification(t *testing.T) {
+	v := NewVerification()
+	ok, _ := asserter.NewErrors(t)
+	ok(v.ScanSources("testdata"))
+	const nav = "github.com/sogvin/website/spec/testdata"
+	events := `{"Action":"run","Package":"` + nav + `","Test":"TestPlot"}
+{"Action":"pass","Package":"` + nav + `","Test":"TestPlot"}
+{"Action":"fail","Package":"example.com/other","Test":"TestPlot"}
+{"Action":"output","Package":"` + nav + `","Test":"TestOther","Output":"    x_test.go:9: verifies: R3\n"}
+{"Action":"fail","Package":"` + nav + `","Test":"TestOther"}
+{"Action":"output","Package":"` + nav + `","Test":"TestMore/sub","Output":"    x_test.go:9: verifies: R2\n"}
+{"Action":"pass","Package":"` + nav + `","Test":"TestMore/sub"}
+{"Action":"pass","Package":"` + nav + `"}
+`
+	ok(v.ReadTestJSON(strings.NewReader(events)))
+	assert := asserter.New(t)
+	assert().Equals(v.Status("R1"), Verified)
+	assert().Equals(v.Status("R2"), Verified)
+	assert().Equals(v.Status("R3"), Failing)
+	assert().Equals(v.Status("R4"), Uncovered)
+	assert().Equals(v.Tests("R1"), []string{"testdata.TestPlot"})
+
+	f := NewFeature("F1", "").Require(
+		NewRequirement("R1", ""), NewRequirement("R3", ""),
+	)
+	matrix := NewTraceabilityMatrix(f)
+	root := Wrap(f.Element(NewHn(1)), matrix)
+	v.Mark(root)
+	assert().Equals(cells(matrix), [][]string{
+		{"Requirement", "Feature", "Status"},
+		{"R1", "F1", "verified"},
+		{"R3", "F1", "failing"},
+	})
+	var marked []string
+	for _, r := range Query(root, "div.requirement") {
+		status := Query(r, "span")
+		assert(len(status) == 1).Fatalf("%s not marked", r.AttrVal("id"))
+		marked = append(marked, status[0].AttrVal("class"))
+	}
+	assert().Equals(marked, []string{"verified", "failing"})
+}
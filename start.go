@@ -69,6 +69,7 @@ func stylingConventions() *Element {
 	    with a solid border.`),
 
 		loadFile("example/no1/main.go"),
+		P(bundleLink("example/no1")),
 		"whereas partial content is without borders.",
 		loadFile("example/no1/main.go", 3, -1),
 
@@ -105,6 +106,8 @@ $ cd go-learn`),
 
 		shellCommand("$ go run .\nHello, world!"),
 
+		P(bundleLink("example/no2")),
+
 		P(`Let's step through each line in the program. The first line
        tells the compiler that this file is part of a package called
        main. Go uses packages to group files in larger projects. The
@@ -119,7 +122,9 @@ $ cd go-learn`),
        fine`),
 
 		loadFile("example/no3/main.go"),
+		P(bundleLink("example/no3")),
 		loadFile("example/no4/main.go"),
+		P(bundleLink("example/no4")),
 
 		P(`You don't want to spend to much time adding spaces and tabs so
 	   your code looks good, let the computer do it for you. For this
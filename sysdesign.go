@@ -10,11 +10,9 @@ import (
 	"github.com/gregoryv/navstar"
 	"github.com/gregoryv/navstar/htapi"
 	. "github.com/gregoryv/web"
-	"github.com/gregoryv/web/toc"
 )
 
 func roleBasedService() *Element {
-	nav := Nav()
 	article := Article(
 		//
 
@@ -29,7 +27,7 @@ func roleBasedService() *Element {
 	    design and elaborate on the naming with an example service for
 	    navigating the stars.`),
 
-		nav,
+		Nav(),
 
 		P(`Code examples found below you can view at `,
 			github("gregoryv/navstar", "gregoryv/navstar"), `.`),
@@ -41,7 +39,9 @@ func roleBasedService() *Element {
         domain we'll be able to elicit concepts and features for our
         system design.`),
 
-		Div(Class("figure"), Img(Src("img/galaxytravel.png"))),
+		figure("galaxytravel", "Galaxy travel services by Future Inc.",
+			Img(Src("img/galaxytravel.png")),
+		),
 
 		P(`The company <b>Future Inc.</b> provides people means to
 	    travel the Milky Way. Customers, browse and order trips on `,
@@ -155,11 +155,14 @@ func roleBasedService() *Element {
 		H3("Navstar package"),
 
 		P(`Navstar implements domain logic related to planning galaxy
-	    flights. It's at the core of our design. Later we'll build
-	    other layers on top of it.`),
+	    flights. It's at the core of our design, as shown in `,
+			ref("navstar-core"), `. Later we'll build other layers on
+	    top of it.`),
 
-		Div(Class("figure"), coreDiagram(`Navstar is the core package
-	    with domain logic`).Inline()),
+		figure("navstar-core", `Navstar is the core package
+	    with domain logic`,
+			svg(coreDiagram()),
+		),
 
 		P(`The type system is the most prominent abstraction the
 	    navstar package provides. It's responsible for synchronizing
@@ -181,8 +184,10 @@ func roleBasedService() *Element {
 	    is implemented by type user but accessible by roles pilot,
 	    passenger and crew member.`),
 
-		Div(Class("figure"), navstarDiagram(`Different roles provide
-		different methods`).Inline()),
+		figure("navstar-roles", `Different roles provide
+		different methods`,
+			svg(navstarDiagram()),
+		),
 
 		P(`We start of by defining all roles in one file together with
 	    the interface, showing partial content below. The reason being
@@ -231,8 +236,10 @@ func roleBasedService() *Element {
 		the only protocol available to us. Httpapi is a mouthful so
 		we'll shorten it to <em>htapi</em>.`),
 
-		Div(Class("figure"), htapiDiagram(`htapi package is separated
-		from the core navstar`).Inline()),
+		figure("htapi", `htapi package is separated
+		from the core navstar`,
+			svg(htapiDiagram()),
+		),
 
 		P(`The htapi provides a router that exposes the navstar
 	    features using its system and roles. Resources are accessible
@@ -246,7 +253,9 @@ func roleBasedService() *Element {
 		P(`A request from a client such as a browser would follow the
 	    below sequence.`),
 
-		usingNavstarSystem("Using navstar system via a HTTP interface").Inline(),
+		figure("navstar-sequence", "Using navstar system via a HTTP interface",
+			svg(usingNavstarSystem()),
+		),
 
 		P(`The router only propagates the request down to the muxer
 		which is an implementation detail and can be freely replaced
@@ -276,8 +285,10 @@ func roleBasedService() *Element {
 	    name for the package holding the application. After some
 	    interations I ended up with with the name <em>starplan</em>.`),
 
-		Div(Class("figure"), starplanDiagram(`Command starplan exposes
-		the htapi via a TCP server.`).Inline()),
+		figure("starplan", `Command starplan exposes
+		the htapi via a TCP server.`,
+			svg(starplanDiagram()),
+		),
 
 		P(`The reason you shouldn't name it e.g. "navstar" is that the
 	    domain of navigating stars will grow and you probably want to
@@ -285,7 +296,7 @@ func roleBasedService() *Element {
 	    commands.`, Br(), `Adding files for some of the mentioned
 	    abstractions we end up with a directory tree like this`),
 
-		shellCommand("$ tree navstar\n"+navstarTree),
+		listing("navstar-tree", "Navstar directory tree", shellCommand("$ tree navstar\n"+navstarTree)),
 
 		H2("Summary"),
 
@@ -298,8 +309,10 @@ func roleBasedService() *Element {
 	    crossing points between the layers. Starplan uses htapi and
 	    navstar, whereas htapi only uses the navstar package.`),
 
-		Div(Class("figure"), InternalDiagram(`Dependency flow, from
-		right to left.`).Inline()),
+		figure("dependency-flow", `Dependency flow, from
+		right to left.`,
+			svg(InternalDiagram()),
+		),
 
 		P(`For other internal domain logic that benefits from
 		alternate naming than <code>navstar.X</code>, structure
@@ -315,7 +328,6 @@ func roleBasedService() *Element {
 
 	//
 	)
-	toc.MakeTOC(nav, article, "h2", "h3")
 	return article
 }
 
@@ -327,7 +339,7 @@ var repoLink = A(
 //go:embed "example/navstar.tree"
 var navstarTree string
 
-func coreDiagram(caption string) *design.Diagram {
+func coreDiagram() *design.Diagram {
 	var (
 		w, h, r, s = 80, 50, 20, 2
 		dx         = w - r
@@ -352,11 +364,10 @@ func coreDiagram(caption string) *design.Diagram {
 	d.Place(starplan).Above(cmd, 0)
 	shape.Move(starplan, right, above)
 
-	d.SetCaption(caption)
 	return d
 }
 
-func htapiDiagram(caption string) *design.Diagram {
+func htapiDiagram() *design.Diagram {
 	var (
 		w, h, r, s = 80, 50, 20, 2
 		dx         = w - r
@@ -381,11 +392,10 @@ func htapiDiagram(caption string) *design.Diagram {
 	d.Place(starplan).Above(cmd, 0)
 	shape.Move(starplan, right, above)
 
-	d.SetCaption(caption)
 	return d
 }
 
-func starplanDiagram(caption string) *design.Diagram {
+func starplanDiagram() *design.Diagram {
 	var (
 		w, h, r, s = 80, 50, 20, 2
 		dx         = w - r
@@ -410,11 +420,10 @@ func starplanDiagram(caption string) *design.Diagram {
 	d.Place(starplan).Above(cmd, 0)
 	shape.Move(starplan, right, above)
 
-	d.SetCaption(caption)
 	return d
 }
 
-func InternalDiagram(caption string) *design.Diagram {
+func InternalDiagram() *design.Diagram {
 	var (
 		w, h, r, s = 80, 50, 20, 2
 		dx         = w - r
@@ -448,11 +457,10 @@ func InternalDiagram(caption string) *design.Diagram {
 
 	d.Place(other).Below(internal, 2*s)
 	d.Place(arrow).At(320, 45)
-	d.SetCaption(caption)
 	return d
 }
 
-func navstarDiagram(caption string) *design.ClassDiagram {
+func navstarDiagram() *design.ClassDiagram {
 	var (
 		d         = design.NewClassDiagram()
 		role      = d.Interface((*navstar.Role)(nil))
@@ -466,11 +474,10 @@ func navstarDiagram(caption string) *design.ClassDiagram {
 	d.Place(passenger, crew).RightOf(pilot, 70)
 	d.VAlignCenter(passenger, role)
 
-	d.SetCaption(caption)
 	return d
 }
 
-func usingNavstarSystem(caption string) *design.SequenceDiagram {
+func usingNavstarSystem() *design.SequenceDiagram {
 	var (
 		d       = design.NewSequenceDiagram()
 		browser = d.Add("browser")
@@ -492,7 +499,6 @@ func usingNavstarSystem(caption string) *design.SequenceDiagram {
 
 	d.Group(router, role, "Protected by role", "blue")
 	d.Group(role, sys, "Unprotected", "red")
-	d.SetCaption(caption)
 	return d
 }
 
@@ -1,17 +1,22 @@
 package website
 
 import (
+	"fmt"
+
 	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
 )
 
-func a4() *CSS {
+// a4 returns the page layout, sized for printing on A4 paper.
+func a4(t tokens.Tokens) *CSS {
+	c := t.Light
 	css := NewCSS()
 	css.Filename = "a4.css"
 
 	css.Style("html, body, header, footer, h1, h2, h3, h4, h5",
 		"margin: 0px 0px",
 		"padding: 0px 0px",
-		"background-color: #ffffff",
+		"background-color: "+c.Background,
 	)
 	css.Style("body",
 		"width: 21cm",
@@ -20,8 +25,8 @@ func a4() *CSS {
 		"text-align: right",
 	)
 	css.Style(".unreleased",
-		"background-color: red",
-		"color: white",
+		"background-color: "+c.Highlight,
+		"color: "+c.OnAccent,
 		"padding: 0 10px",
 	)
 	css.Style("p",
@@ -34,10 +39,10 @@ func a4() *CSS {
 		"margin-top: 0cm",
 		"margin-bottom: 0.5cm",
 		"padding-top: 0.2cm",
-		"padding-left: 4cm",
+		"padding-left: "+t.Indent,
 	)
 	css.Style("h1, h2",
-		"margin-left: -4cm",
+		"margin-left: -"+t.Indent,
 	)
 	css.Style("h3",
 		"margin-bottom:0.5cm",
@@ -46,11 +51,11 @@ func a4() *CSS {
 		"text-align: right",
 	)
 	css.Style("pre code",
-		"font-size: 14px",
+		"font-size: "+t.CodeSize,
 	)
 	css.Style("pre code a",
 		"text-decoration: none",
-		"color: black",
+		"color: "+c.Text,
 		"font-weight: bold",
 	)
 	css.Style("pre code a:hover",
@@ -65,10 +70,10 @@ func a4() *CSS {
 		"tab-size: 4",
 	)
 	css.Style(".command, .srcfile",
-		"margin-top: 1.6em",
-		"margin-bottom: 1.6em",
-		"padding-left: 1.6em",
-		"background-color: #eaeaea",
+		"margin-top: "+t.Space.Large,
+		"margin-bottom: "+t.Space.Large,
+		"padding-left: "+t.Space.Large,
+		"background-color: "+c.Code,
 	)
 	css.Style(".srcfile code",
 		"padding: .6em 0 .6em 0",
@@ -76,30 +81,30 @@ func a4() *CSS {
 		"display: block",
 	)
 	css.Style(".complete",
-		"border: 1px solid #727272",
+		"border: 1px solid "+c.Border,
 	)
 	css.Style(".filename",
 		"display: block",
 		"text-align: right",
 		"margin-bottom: -1.6em",
-		"font-family: mono",
+		"font-family: "+t.CodeFont,
 		"font-size: 12px",
 	)
 	css.Style(".command",
-		"border-left: 7px #727272 solid",
-		"padding: .6em 1.6em .6em 1.6em",
+		fmt.Sprintf("border-left: 7px %s solid", c.Border),
+		fmt.Sprintf("padding: .6em %s .6em %s", t.Space.Large, t.Space.Large),
 	)
 	css.Style(".sidenote",
-		"border: 1px solid black",
+		"border: 1px solid "+c.Text,
 		"width: 3.3cm",
 		"padding: 1px 1px",
 		"font-size: 0.8em",
 		"position: absolute",
-		"margin-left: -4cm",
+		"margin-left: -"+t.Indent,
 	)
 	css.Style(".inner",
 		"padding: 0.1cm",
-		"border: 1px solid black",
+		"border: 1px solid "+c.Text,
 	)
 	css.Style("article.toc h3",
 		"margin-left: 0",
@@ -111,18 +116,38 @@ func a4() *CSS {
 		"padding: 0px",
 		"list-style: none",
 	)
-	css.Style("div.figure",
+	css.Style("figure.figure",
+		"text-align: center",
+	)
+	css.Style("figure",
+		"margin: 1em 0",
+	)
+	css.Style("figcaption",
+		"font-size: 0.8em",
+		"font-style: italic",
 		"text-align: center",
 	)
+	css.Style("figcaption span.number",
+		"padding-right: 0.4em",
+		"font-weight: bold",
+	)
 
 	css.Style("h1 a, h2 a, h3 a, h4 a, h5 a",
 		"text-decoration: none",
-		"color: black",
+		"color: "+c.Text,
 	)
 	css.Style("h1:hover a, h2:hover a, h3:hover a, h4:hover a, h5:hover a",
 		"text-decoration: underline",
 	)
 
+	css.Style("a.self",
+		"padding-left: 0.2em",
+		"color: "+c.Muted,
+		"visibility: hidden",
+	)
+	css.Style("h2:hover a.self, h3:hover a.self, figcaption:hover a.self",
+		"visibility: visible",
+	)
 	css.Style("article nav ul",
 		"list-style-type: square",
 	)
@@ -149,18 +174,95 @@ func a4() *CSS {
 	return css
 }
 
-func theme() *CSS {
+// screen returns styles for reading on screens of any size. Narrow
+// screens drop the wide left margin of the A4 layout and readers
+// preferring dark colors get the dark palette. Print is unaffected.
+func screen(t tokens.Tokens) *CSS {
 	css := NewCSS()
-	css.Filename = "theme.css"
+	css.Filename = "screen.css"
 
-	fonts := "Inconsolata|Source+Sans+Pro"
-	css.Import("https://fonts.googleapis.com/css?family=" + fonts)
+	narrow := css.Media("screen and (max-width: 22cm)")
+	narrow.Style("body",
+		"width: auto",
+	)
+	narrow.Style("html, body",
+		"margin: 0px 0px",
+		"padding: 3px 8px",
+	)
+	narrow.Style("article",
+		"padding-left: 0",
+	)
+	narrow.Style("h1, h2, .sidenote",
+		"margin-left: 0",
+	)
+	narrow.Style(".sidenote",
+		"position: static",
+		"width: auto",
+		"margin: 0.5em 0",
+	)
+	narrow.Style("pre",
+		"overflow-x: auto",
+	)
+	narrow.Style("img, figure svg",
+		"max-width: 100%",
+		"height: auto",
+	)
+
+	dark := css.Media("screen and (prefers-color-scheme: dark)")
+	colors(dark, t.Dark)
+	dark.Style("figure svg",
+		"background-color: "+t.Light.Background,
+		"border-radius: 4px",
+	)
+	return css
+}
+
+// colors styles elements with colors of the given palette.
+func colors(css *CSS, p tokens.Palette) {
+	css.Style("html, body, header, footer, h1, h2, h3, h4, h5",
+		"background-color: "+p.Background,
+		"color: "+p.Text,
+	)
+	css.Style("a",
+		"color: "+p.Link,
+	)
+	css.Style("h1 a, h2 a, h3 a, h4 a, h5 a, pre code a",
+		"color: "+p.Text,
+	)
+	css.Style("a.self, .dialog .elicits",
+		"color: "+p.Muted,
+	)
+	css.Style(".command, .srcfile",
+		"background-color: "+p.Code,
+	)
+	css.Style(".complete",
+		fmt.Sprintf("border: 1px solid %s", p.Border),
+	)
+	css.Style(".command",
+		fmt.Sprintf("border-left: 7px %s solid", p.Border),
+	)
+	css.Style(".sidenote, .inner",
+		fmt.Sprintf("border-color: %s", p.Text),
+	)
+	css.Style(".unreleased",
+		"background-color: "+p.Highlight,
+		"color: "+p.OnAccent,
+	)
+}
+
+// theme returns fonts and styles not related to layout.
+func theme(t tokens.Tokens) *CSS {
+	css := NewCSS()
+	css.Filename = "theme.css"
 
+	if t.FontImport != "" {
+		css.Import(t.FontImport)
+	}
 	css.Style("html, body",
-		"font-family: 'Source Sans Pro', sans-serif",
+		"font-family: "+t.Font,
 	)
 	css.Style("code",
-		"font-family: Inconsolata",
+		"font-family: "+t.CodeFont,
 	)
 	css.Style("quote, blockquote",
 		"font-style: italic",
@@ -169,7 +271,7 @@ func theme() *CSS {
 	print := css.Media("print")
 	print.Style("    a",
 		"text-decoration: none",
-		"color: black",
+		"color: "+t.Light.Text,
 	)
 
 	return css
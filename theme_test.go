@@ -0,0 +1,83 @@
+package website
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw"
+	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
+)
+
+func Test_themes_use_tokens(t *testing.T) {
+	tk := tokens.Default
+	tk.Light.Code = "#010203"
+	tk.Dark.Background = "#040506"
+	tk.CodeSize = "99px"
+	tk.FontImport = ""
+
+	assert := asserter.New(t)
+	paper := rules(a4(tk))
+	assert().Equals(paper["pre code"], []string{"font-size: 99px"})
+	assert().Equals(paper[".command, .srcfile"], []string{
+		"margin-top: " + tk.Space.Large,
+		"margin-bottom: " + tk.Space.Large,
+		"padding-left: " + tk.Space.Large,
+		"background-color: #010203",
+	})
+	const dark = "@media screen and (prefers-color-scheme: dark) "
+	assert().Equals(rules(screen(tk))[dark+"html, body, header, footer, h1, h2, h3, h4, h5"], []string{
+		"background-color: #040506", "color: " + tk.Dark.Text,
+	})
+	_, imported := rules(theme(tk))["@import"]
+	assert(!imported).Error("import without FontImport")
+}
+
+// rules returns declarations of each rule in css by selector, prefixed
+// with the media query if any.
+func rules(css *CSS) map[string][]string {
+	var buf bytes.Buffer
+	css.WriteTo(&buf)
+	res := make(map[string][]string)
+	var media, selector string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@import"):
+			res["@import"] = append(res["@import"], line)
+		case strings.HasPrefix(line, "@media"):
+			media = strings.TrimSuffix(line, "{") + " "
+		case strings.HasSuffix(line, " {"):
+			selector = media + strings.TrimSuffix(line, " {")
+			if _, found := res[selector]; !found {
+				res[selector] = make([]string, 0)
+			}
+		case line == "}" && selector == "":
+			media = ""
+		case line == "}":
+			selector = ""
+		case selector != "":
+			res[selector] = append(res[selector], strings.TrimSuffix(line, ";"))
+		}
+	}
+	return res
+}
+
+func Test_styleDiagrams(t *testing.T) {
+	p := tokens.Light
+	p.Stroke = "#010203"
+	p.Ink = "#040506"
+	defaults := fmt.Sprint(draw.DefaultClassAttributes)
+	style := diagramStyle(p)
+
+	article := Article(figure("colors", "Colors", svg(colorSchemeDiagram(p))))
+	styleDiagrams(article, style)
+	got := article.String()
+	assert := asserter.New(t)
+	assert().Contains(got, `<circle stroke="#010203" stroke-width="1" fill="`+p.Fill+`"`)
+	assert(!strings.Contains(got, `class="circle0"`)).Error("class not styled")
+	assert().Contains(style["area-red"], `stroke="#040506"`)
+	assert().Equals(fmt.Sprint(draw.DefaultClassAttributes), defaults)
+}
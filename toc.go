@@ -0,0 +1,68 @@
+package website
+
+import (
+	. "github.com/gregoryv/web"
+)
+
+// tocHeadings are the heading levels listed in a table of contents.
+var tocHeadings = []string{"h2", "h3"}
+
+// addTOC inserts a table of contents into articles with at least
+// threshold anchored headings, see anchorHeadings. An empty nav
+// element in the article marks where the table of contents goes,
+// otherwise it's placed after the h1. A threshold less than one
+// disables it.
+func addTOC(article *Element, threshold int) {
+	if threshold < 1 {
+		return
+	}
+	headings := queryHeadings(article, tocHeadings...)
+	if len(headings) < threshold {
+		return
+	}
+	nav := emptyNav(article)
+	if nav == nil {
+		nav = Nav()
+		insertAfterH1(article, nav)
+	}
+	ul := Ul()
+	for _, h := range headings {
+		id := h.AttrVal("id")
+		ul.With(Li(Class(h.Name), A(Href("#"+id), headingText(h))))
+	}
+	nav.With(ul)
+}
+
+// queryHeadings returns all elements with any of the given names in
+// document order.
+func queryHeadings(root *Element, names ...string) []*Element {
+	res := make([]*Element, 0)
+	WalkElements(root, func(e *Element) {
+		for _, name := range names {
+			if e.Name == name {
+				res = append(res, e)
+			}
+		}
+	})
+	return res
+}
+
+func emptyNav(article *Element) *Element {
+	for _, nav := range Query(article, "nav") {
+		if len(nav.Children) == 0 {
+			return nav
+		}
+	}
+	return nil
+}
+
+func insertAfterH1(article *Element, v interface{}) {
+	for i, c := range article.Children {
+		if e, ok := c.(*Element); ok && e.Name == "h1" {
+			rest := append([]interface{}{v}, article.Children[i+1:]...)
+			article.Children = append(article.Children[:i+1], rest...)
+			return
+		}
+	}
+	article.Children = append([]interface{}{v}, article.Children...)
+}
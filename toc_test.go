@@ -0,0 +1,38 @@
+package website
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_addTOC(t *testing.T) {
+	article := Article(
+		H1("Title"),
+		P("intro"),
+		H2("Setup"),
+		H3("Setup"),
+		H2("Run it", Id("run")),
+	)
+	anchorHeadings(article)
+	addTOC(article, 3)
+
+	assert := asserter.New(t)
+	nav, ok := article.Children[1].(*Element)
+	assert(ok && nav.Name == "nav").Fatal("toc not placed after h1")
+	var entries [][]string
+	for _, li := range Query(nav, "li") {
+		a := MustQueryOne(li, "a")
+		entries = append(entries, []string{li.AttrVal("class"), a.AttrVal("href"), a.Text()})
+	}
+	assert().Equals(entries, [][]string{
+		{"h2", "#setup", "Setup"},
+		{"h3", "#setup_2", "Setup"},
+		{"h2", "#run", "Run it"},
+	})
+
+	few := Article(H1("Title"), H2("Only"))
+	addTOC(few, 3)
+	assert().Equals(len(Query(few, "nav")), 0)
+}
@@ -0,0 +1,137 @@
+// Package tokens defines design values shared by page themes and
+// diagrams, so they look the same everywhere.
+package tokens
+
+// Default tokens of the website and specifications. Change these to
+// rebrand pages and diagrams in one go.
+var Default = Tokens{
+	Light: Light,
+	Dark:  Dark,
+
+	Font:       "'Source Sans Pro', " + SystemFont,
+	CodeFont:   "Inconsolata, " + SystemCodeFont,
+	FontImport: "https://fonts.googleapis.com/css?family=Inconsolata|Source+Sans+Pro",
+	CodeSize:   "14px",
+
+	Indent: "4cm",
+	Space: Spacing{
+		Small:  "0.382em",
+		Medium: "0.618em",
+		Large:  "1.618em",
+	},
+}
+
+// System font stacks used when named fonts are unavailable, e.g.
+// offline without bundled fonts.
+const (
+	SystemFont     = "system-ui, -apple-system, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif"
+	SystemCodeFont = "ui-monospace, Menlo, Consolas, 'DejaVu Sans Mono', monospace"
+)
+
+// Tokens are the design values of one theme.
+type Tokens struct {
+	Light Palette
+	Dark  Palette // on screens preferring a dark color scheme
+
+	Font       string // family of text
+	CodeFont   string // family of code
+	FontImport string // stylesheet providing the fonts, empty for none
+	CodeSize   string // of code blocks
+
+	Indent string // of A4 articles, headings and side notes use it
+	Space  Spacing
+}
+
+// Spacing between and around elements.
+type Spacing struct {
+	Small  string
+	Medium string
+	Large  string
+}
+
+// Palette names colors of a color scheme.
+type Palette struct {
+	Background string
+	Text       string
+	Muted      string // e.g. self links and annotations
+	Code       string // background of code blocks
+	Border     string
+	Rule       string // light lines, e.g. in dialogs
+	Link       string
+	Highlight  string // e.g. unreleased versions
+	Success    string
+	Failure    string
+	OnAccent   string // text on highlight, success and failure
+
+	Stroke string // outlines of diagram shapes
+	Ink    string // diagram lines and printed text
+
+	// Fills of diagram shapes
+	Fill     string
+	External string
+	Note     string
+	Orange   string
+	Red      string
+	Green    string
+	Blue     string
+}
+
+// Fills returns the diagram fills, from plain to emphasized.
+func (me Palette) Fills() []string {
+	return []string{
+		me.Fill, me.External, me.Note, me.Orange, me.Red, me.Green, me.Blue,
+	}
+}
+
+// Light is the default color scheme, also used for printing.
+var Light = Palette{
+	Background: "#ffffff",
+	Text:       "#000000",
+	Muted:      "#949494",
+	Code:       "#eaeaea",
+	Border:     "#727272",
+	Rule:       "#e2e2e2",
+	Link:       "#0000ee",
+	Highlight:  "#cc0000",
+	Success:    "green",
+	Failure:    "#cc0000",
+	OnAccent:   "#ffffff",
+
+	Stroke: "#d3d3d3",
+	Ink:    "#000000",
+
+	Fill:     "#ffffff",
+	External: "#e2e2e2",
+	Note:     "#ffffcc",
+	Orange:   "#ffcc99",
+	Red:      "#ff9999",
+	Green:    "#ccff99",
+	Blue:     "#99e6ff",
+}
+
+// Dark is used on screens when the reader prefers a dark color
+// scheme. Diagrams keep the light fills on a light background.
+var Dark = Palette{
+	Background: "#1e1f22",
+	Text:       "#dcdcdc",
+	Muted:      "#6a6a6a",
+	Code:       "#2b2d31",
+	Border:     "#6e6e6e",
+	Rule:       "#3c3f44",
+	Link:       "#8ab4f8",
+	Highlight:  "#c0392b",
+	Success:    "#2e7d32",
+	Failure:    "#c0392b",
+	OnAccent:   "#ffffff",
+
+	Stroke: Light.Stroke,
+	Ink:    Light.Ink,
+
+	Fill:     Light.Fill,
+	External: Light.External,
+	Note:     Light.Note,
+	Orange:   Light.Orange,
+	Red:      Light.Red,
+	Green:    Light.Green,
+	Blue:     Light.Blue,
+}
@@ -0,0 +1,345 @@
+package website
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// validatePages renders all pages and returns an error listing
+// unbalanced tags, invalid nesting and duplicate ids. Raw html, e.g.
+// rendered markdown, is not checked by the web package so problems are
+// only visible in the rendered result.
+func (me *Website) validatePages() error {
+	problems := make([]string, 0)
+	for _, page := range me.pages {
+		var buf bytes.Buffer
+		if _, err := page.WriteTo(&buf); err != nil {
+			return err
+		}
+		for _, err := range validateHTML(page.Filename, buf.Bytes()) {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid html:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// voidElements have no end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements contain text which is not parsed as html
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// forbiddenIn maps elements to ancestors they must not have.
+var forbiddenIn = map[string][]string{
+	"header": {"header", "footer"},
+	"footer": {"header", "footer"},
+	"a":      {"a", "button"},
+	"form":   {"form"},
+	"button": {"a", "button"},
+	"main":   {"article", "aside", "header", "footer", "nav"},
+}
+
+// optionalEnd elements may omit their end tag in HTML5.
+var optionalEnd = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true,
+	"dt": true, "dd": true, "rt": true, "rp": true, "optgroup": true,
+	"option": true, "colgroup": true, "caption": true, "thead": true,
+	"tbody": true, "tfoot": true, "tr": true, "td": true, "th": true,
+}
+
+// impliedEnd maps start tags to the open elements they close, e.g. a
+// new li ends the previous one.
+var impliedEnd = map[string][]string{
+	"body":     {"head"},
+	"li":       {"li"},
+	"dt":       {"dt", "dd"},
+	"dd":       {"dt", "dd"},
+	"rt":       {"rt", "rp"},
+	"rp":       {"rt", "rp"},
+	"option":   {"option"},
+	"optgroup": {"option", "optgroup"},
+	"thead":    {"caption", "colgroup"},
+	"tbody":    {"caption", "colgroup", "thead", "tr", "td", "th"},
+	"tfoot":    {"caption", "colgroup", "thead", "tbody", "tr", "td", "th"},
+	"tr":       {"caption", "colgroup", "tr", "td", "th"},
+	"td":       {"td", "th"},
+	"th":       {"td", "th"},
+}
+
+// blockElements end an open p element.
+var blockElements = []string{
+	"address", "article", "aside", "blockquote", "details", "div", "dl",
+	"dd", "dt", "fieldset", "figcaption", "figure", "footer", "form",
+	"h1", "h2", "h3", "h4", "h5", "h6", "header", "hr", "li", "main",
+	"menu", "nav", "ol", "p", "pre", "section", "table", "ul",
+}
+
+func init() {
+	for _, name := range blockElements {
+		impliedEnd[name] = append(impliedEnd[name], "p")
+	}
+}
+
+type openTag struct {
+	name string
+	line int
+}
+
+// validateHTML returns problems found when parsing the html document,
+// one per problem.
+func validateHTML(filename string, data []byte) []error {
+	var (
+		problems = make([]error, 0)
+		stack    = make([]openTag, 0)
+		ids      = &idCache{used: make(map[string]int)}
+	)
+	report := func(line int, format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf("%s:%v: %s",
+			filename, line, fmt.Sprintf(format, args...),
+		))
+	}
+	z := &tokenizer{data: data, line: 1}
+	for {
+		tok, ok := z.next()
+		if !ok {
+			break
+		}
+		switch tok.kind {
+		case startTag:
+			for len(stack) > 0 && contains(impliedEnd[tok.name], stack[len(stack)-1].name) {
+				stack = stack[:len(stack)-1]
+			}
+			for _, name := range forbiddenIn[tok.name] {
+				for _, open := range stack {
+					if open.name == name {
+						report(tok.line, "<%s> inside <%s> from line %v",
+							tok.name, name, open.line,
+						)
+					}
+				}
+			}
+			if id := tok.attrs["id"]; id != "" {
+				if line, dup := ids.add(id, tok.line); dup {
+					report(tok.line, "duplicate id %q, first used on line %v",
+						id, line,
+					)
+				}
+			}
+			if tok.selfClosing || voidElements[tok.name] {
+				continue
+			}
+			stack = append(stack, openTag{tok.name, tok.line})
+			if rawTextElements[tok.name] {
+				z.skipRawText(tok.name)
+			}
+
+		case endTag:
+			if voidElements[tok.name] {
+				report(tok.line, "end tag of void element </%s>", tok.name)
+				continue
+			}
+			i := len(stack) - 1
+			for i >= 0 && stack[i].name != tok.name {
+				i--
+			}
+			if i == -1 {
+				report(tok.line, "unexpected </%s>", tok.name)
+				continue
+			}
+			for _, open := range stack[i+1:] {
+				if !optionalEnd[open.name] {
+					report(open.line, "unclosed <%s>", open.name)
+				}
+			}
+			stack = stack[:i]
+		}
+	}
+	for _, open := range stack {
+		if !optionalEnd[open.name] {
+			report(open.line, "unclosed <%s>", open.name)
+		}
+	}
+	return problems
+}
+
+func contains(names []string, name string) bool {
+	for _, v := range names {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+type tagKind int
+
+const (
+	startTag tagKind = iota
+	endTag
+)
+
+type tag struct {
+	kind        tagKind
+	name        string
+	attrs       map[string]string
+	selfClosing bool
+	line        int
+}
+
+// tokenizer splits html into tags, enough for validation. Text,
+// comments and declarations are skipped.
+type tokenizer struct {
+	data []byte
+	pos  int
+	line int
+}
+
+func (me *tokenizer) next() (tag, bool) {
+	for me.pos < len(me.data) {
+		i := bytes.IndexByte(me.data[me.pos:], '<')
+		if i == -1 {
+			me.advance(len(me.data) - me.pos)
+			break
+		}
+		me.advance(i)
+		rest := me.data[me.pos:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			me.skipPast("-->")
+		case bytes.HasPrefix(rest, []byte("<!")), bytes.HasPrefix(rest, []byte("<?")):
+			me.skipPast(">")
+		case bytes.HasPrefix(rest, []byte("</")):
+			line := me.line
+			me.advance(2)
+			name := me.name()
+			me.skipPast(">")
+			return tag{kind: endTag, name: name, line: line}, true
+		case len(rest) > 1 && isLetter(rest[1]):
+			return me.startTag(), true
+		default:
+			me.advance(1) // lone <
+		}
+	}
+	return tag{}, false
+}
+
+func (me *tokenizer) startTag() tag {
+	tok := tag{
+		kind:  startTag,
+		line:  me.line,
+		attrs: make(map[string]string),
+	}
+	me.advance(1)
+	tok.name = me.name()
+	for me.pos < len(me.data) {
+		me.skipSpace()
+		switch {
+		case me.pos >= len(me.data):
+		case me.data[me.pos] == '>':
+			me.advance(1)
+			return tok
+		case bytes.HasPrefix(me.data[me.pos:], []byte("/>")):
+			tok.selfClosing = true
+			me.advance(2)
+			return tok
+		default:
+			name := me.name()
+			if name == "" {
+				me.advance(1) // garbage
+				continue
+			}
+			me.skipSpace()
+			var val string
+			if me.pos < len(me.data) && me.data[me.pos] == '=' {
+				me.advance(1)
+				me.skipSpace()
+				val = me.value()
+			}
+			tok.attrs[name] = val
+		}
+	}
+	return tok
+}
+
+func (me *tokenizer) name() string {
+	start := me.pos
+	for me.pos < len(me.data) {
+		c := me.data[me.pos]
+		if c == '>' || c == '/' || c == '=' || isSpace(c) {
+			break
+		}
+		me.pos++
+	}
+	return strings.ToLower(string(me.data[start:me.pos]))
+}
+
+func (me *tokenizer) value() string {
+	if me.pos >= len(me.data) {
+		return ""
+	}
+	if q := me.data[me.pos]; q == '"' || q == '\'' {
+		end := bytes.IndexByte(me.data[me.pos+1:], q)
+		if end == -1 {
+			end = len(me.data) - me.pos - 1
+		}
+		v := string(me.data[me.pos+1 : me.pos+1+end])
+		me.advance(end + 2)
+		return v
+	}
+	start := me.pos
+	for me.pos < len(me.data) && !isSpace(me.data[me.pos]) && me.data[me.pos] != '>' {
+		me.pos++
+	}
+	return string(me.data[start:me.pos])
+}
+
+// skipRawText moves to the end tag of the named raw text element.
+func (me *tokenizer) skipRawText(name string) {
+	end := bytes.Index(bytes.ToLower(me.data[me.pos:]), []byte("</"+name))
+	if end == -1 {
+		end = len(me.data) - me.pos
+	}
+	me.advance(end)
+}
+
+func (me *tokenizer) skipPast(v string) {
+	end := bytes.Index(me.data[me.pos:], []byte(v))
+	if end == -1 {
+		me.advance(len(me.data) - me.pos)
+		return
+	}
+	me.advance(end + len(v))
+}
+
+func (me *tokenizer) skipSpace() {
+	for me.pos < len(me.data) && isSpace(me.data[me.pos]) {
+		me.advance(1)
+	}
+}
+
+// advance moves n bytes forward counting lines.
+func (me *tokenizer) advance(n int) {
+	if me.pos+n > len(me.data) {
+		n = len(me.data) - me.pos
+	}
+	me.line += bytes.Count(me.data[me.pos:me.pos+n], []byte("\n"))
+	me.pos += n
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
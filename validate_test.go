@@ -0,0 +1,74 @@
+package website
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_validateHTML(t *testing.T) {
+	doc := `<!DOCTYPE html>
+<html><head><meta charset="utf-8"/><title>a <b></title>
+<style>p > a { color: red }</style></head>
+<body>
+<header><header>x</header></header>
+<p id="a">text <div>block</div></p>
+<a href="#a"><a>inner</a></a>
+<span id='a'>dup</span>
+<ul><li>open
+</ul>
+<br>
+</em>
+<!-- <div> -->
+<section>`
+	var got []string
+	for _, err := range validateHTML("x.html", []byte(doc)) {
+		got = append(got, err.Error())
+	}
+	exp := []string{
+		"x.html:5: <header> inside <header> from line 5",
+		"x.html:6: unexpected </p>", // div ends the paragraph
+		"x.html:7: <a> inside <a> from line 7",
+		`x.html:8: duplicate id "a", first used on line 6`,
+		"x.html:12: unexpected </em>",
+		"x.html:14: unclosed <section>",
+	}
+	assert := asserter.New(t)
+	assert().Equals(strings.Join(got, "\n"), strings.Join(exp, "\n"))
+}
+
+func Test_validateHTML_impliedEnd(t *testing.T) {
+	doc := `<!DOCTYPE html>
+<html><head><title>a</title>
+<body>
+<p>one
+<p>two
+<ul><li>a<li>b <ul><li>c</ul></ul>
+<dl><dt>x<dd>y<dt>z</dl>
+<table><tr><td>1<td>2<tr><th>3</table>
+<p>three <span>open</p>`
+	var got []string
+	for _, err := range validateHTML("x.html", []byte(doc)) {
+		got = append(got, err.Error())
+	}
+	exp := []string{
+		"x.html:9: unclosed <span>",
+	}
+	assert := asserter.New(t)
+	assert().Equals(strings.Join(got, "\n"), strings.Join(exp, "\n"))
+}
+
+func Test_saveAll_validatesFirst(t *testing.T) {
+	site := &Website{refs: make(map[string]*reference)}
+	site.ToSaver = &saveAll{site}
+	site.add(NewFile("a.html", Html(Body(Div("<section>")))))
+	base := t.TempDir()
+	_, bad := asserter.NewErrors(t)
+	bad(site.SaveTo(base)) // invalid html
+	entries, _ := os.ReadDir(base)
+	assert := asserter.New(t)
+	assert().Equals(len(entries), 0)
+}
@@ -2,24 +2,50 @@ package website
 
 import (
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
+	"github.com/gregoryv/draw"
 	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/spec"
+	"github.com/sogvin/website/tokens"
 )
 
-func NewWebsite() *Website {
+func NewWebsite(options ...SiteOption) *Website {
 	title := "Software Engineering"
 	author := "Gregory Vin&ccaron;i&cacute;"
 	site := Website{
 		title:  title,
 		author: author,
+
+		tocThreshold: 4,
+		refs:         make(map[string]*reference),
+		layouts:      defaultLayouts(),
+	}
+	for _, opt := range options {
+		opt(&site)
 	}
 	site.ToSaver = &saveAll{&site}
-	site.AddThemes(a4(), theme())
+	t := tokens.Default
+	if site.offline {
+		t.FontImport = ""
+	}
+	fonts := theme(t)
+	if site.fontDir != "" {
+		site.fonts, site.err = loadFonts(site.fontDir)
+		addFontFaces(fonts, site.fonts)
+	}
+	site.AddThemes(a4(t), fonts, screen(t))
+	site.diagramStyle = diagramStyle(t.Light)
+	specLinks, err := site.addSpecification(t)
+	if site.err == nil {
+		site.err = err
+	}
 
 	article := Article(Class("toc"),
 		H1(title, " - Skills &amp; Drills"),
-		Img(Src("img/office.jpg")),
+		Img(Src("img/office.jpg"), Alt("Desk in an office")),
 		P("Notes by ", author),
 
 		H2("Preface"),
@@ -39,6 +65,10 @@ func NewWebsite() *Website {
 
 		P(`Skill of presenting a problem domain with a scoped solution
 		in mind.`),
+		Ul(
+			site.AddPage("Plan", spec.NewSpecificationArticle(), spec.Theme(t)),
+		),
+		specLinks,
 
 		H2("Design"),
 
@@ -48,7 +78,7 @@ func NewWebsite() *Website {
 		up a system and why.`),
 
 		Ul(
-			site.AddPage("Design", componentsDiagram()),
+			site.AddPage("Design", componentsDiagram(t.Light)),
 			site.AddPage("Design", roleBasedService()),
 		),
 
@@ -115,124 +145,212 @@ func NewWebsite() *Website {
 		H2("References"),
 		Ul(
 			site.AddPage("References", packageRefs()),
+			site.addFigureList(),
 		),
 	)
 
-	site.add(NewFile("index.html",
-		Html(Lang("en"),
-			Head(
-				Meta(Charset("utf-8")),
-				Meta(
-					Name("viewport"),
-					Content("width=device-width, initial-scale=1.0"),
-				),
-				stylesheet("theme.css"),
-				stylesheet("a4.css"),
-				Title(site.title),
-			),
-			Body(
-				Header(Code(
-					A(Href("changelog.html"), versionField()),
-				)),
-				article,
-				Footer(),
-			),
-		),
-	))
+	anchorHeadings(article)
+	index, err := site.layout("index")
+	if err != nil {
+		panic(err) // index is a default layout
+	}
+	site.add(index.Render(&site, "index.html", "", article))
 
 	site.AddPage("", Changelog)
 
 	return &site
 }
 
+// SiteOption configures a website before any pages are added.
+type SiteOption func(*Website)
+
+// WithPlayground sets base url of a self hosted playground used in
+// drill run links, see Playground. Without one, or with an empty base,
+// drills have no run links.
+func WithPlayground(base string) SiteOption {
+	return func(w *Website) {
+		if base == "" {
+			w.playground = nil
+			return
+		}
+		w.playground = NewPlayground(base)
+	}
+}
+
+// WithDrillRunner adds a run button to drill pages posting to the
+// given url, see cmd/drillrun.
+func WithDrillRunner(url string) SiteOption {
+	return func(w *Website) {
+		w.drillRunner = url
+	}
+}
+
+// WithTOC sets the number of headings an article must have for a
+// table of contents to be added, zero disables it.
+func WithTOC(threshold int) SiteOption {
+	return func(w *Website) {
+		w.tocThreshold = threshold
+	}
+}
+
 type Website struct {
 	ToSaver
 
 	title  string
 	author string
-	pages  []*Page
+	pages  []*Page // filenames relative to the site root
 	themes []*CSS
-	drills []*Page
+
+	bundles     []*bundle
+	playground  *Playground // optional
+	drillRunner string
+
+	diagramStyle draw.ClassAttributes // see styleDiagrams
+
+	tocThreshold int
+	refs         map[string]*reference
+
+	figureList bool
+	figures    []*listedFigure
+
+	offline bool
+	fontDir string
+	fonts   []*fontFace
+	err     error // first error adding pages, returned on save
+
+	auditLevel Severity
+
+	baseURL string
+	layouts map[string]*Layout
 }
 
-// AddPage creates a new page and returns a link to it
-func (me *Website) AddPage(right string, article *Element) *Element {
+// AddPage creates a new page and returns a link to it. Optional themes
+// are saved with the website and linked after the common themes.
+func (me *Website) AddPage(right string, article *Element, themes ...*CSS) *Element {
+	link, err := me.AddPageAs("article", right, article, themes...)
+	if err != nil {
+		panic(err) // article is a default layout
+	}
+	return link
+}
+
+// AddPageAs is like AddPage but renders the page with the named
+// layout, see WithLayout. The returned link is relative to the site
+// root. Fails if there is no such layout.
+func (me *Website) AddPageAs(layout, right string, article *Element, themes ...*CSS) (*Element, error) {
+	l, err := me.layout(layout)
+	if err != nil {
+		return nil, err
+	}
 	title := MustQueryOne(article, "h1").Text()
 	filename := filenameFrom(title) + ".html"
+	anchorHeadings(article)
+	addTOC(article, me.tocThreshold)
+	styleDiagrams(article, me.diagramStyle)
+	me.registerPage(title, path.Join(l.Dir, filename), article)
 
-	backlink := A(Href("index.html"), me.title)
-	if right != "" {
-		backlink = Code(
-			right, " - ", A(Href("index.html"), me.title),
-		)
+	for _, theme := range themes {
+		me.addTheme(theme)
 	}
-	page := NewFile(filename,
-		Html(Lang("en"),
-			Head(
-				Meta(Charset("utf-8")),
-				Meta(
-					Name("viewport"),
-					Content("width=device-width, initial-scale=1.0"),
-				),
-				stylesheet("theme.css"),
-				stylesheet("a4.css"),
-				Title(stripTags(title)+" - "+me.title)),
-			Body(
-				Header(backlink),
-				article,
-				Footer(me.author),
-			),
-		),
-	)
+	page := l.Render(me, filename, right, article, themes...)
 	me.add(page)
+	for _, a := range Query(article, "a.bundle") {
+		dir := strings.TrimSuffix(a.AttrVal("href"), ".zip")
+		me.bundles = append(me.bundles, newExampleBundle(dir))
+	}
+	return linkToPage(page), nil
+}
+
+// AddDrill creates a drill page and returns a link to it. Drills with
+// exercise marked regions are rendered with the regions blanked and
+// the solution on a separate page.
+func (me *Website) AddDrill(right, args string, filename string) *Element {
+	l, err := me.layout("drill")
+	if err != nil {
+		panic(err) // drill is a default layout
+	}
+	src := loadAs(filename, "init", "main")
+	page := toHtmlFile(filepath.Base(filename))
+	me.register(
+		path.Join(l.Dir, refKey(drillName(filename))), drillTitle(filename),
+		path.Join(l.Dir, page),
+	)
+	starter := newDrillBundle(l.Dir, args, filename)
+	me.bundles = append(me.bundles, starter)
+	links := Ul()
+	if me.playground != nil {
+		links.With(Li(me.runLink(drillMain(filename))))
+	}
+	links.With(Li(starter.Link(l.Dir)))
+	if me.drillRunner != "" {
+		links.With(Li(runForm(me.drillRunner, filename, args)))
+	}
+	if !hasExercise(src) {
+		article := Article(
+			drillSource(filename, src),
+			links,
+			example(args, filename),
+		)
+		me.add(l.Render(me, page, right, article))
+		return linkDrill(l.Dir, filename)
+	}
 
-	return linkToPage(page)
+	ex := newExercise(args, filename)
+	me.bundles = append(me.bundles, ex.Bundle(l.Dir))
+	me.add(l.Render(me, page, right, Article(
+		drillSource(filename, blankExercises(src)),
+		ex.Links(),
+		example(args, filename),
+	)))
+	me.add(l.Render(me, ex.solutionFile(), right, Article(
+		drillSource(filename, stripExerciseMarks(src)),
+		links,
+		example(args, filename),
+	)))
+	return linkDrill(l.Dir, filename)
 }
 
-func linkTo(article *Element) *Element {
-	title := MustQueryOne(article, "h1").Text()
-	filename := filenameFrom(title) + ".html"
-	return A(Href(filename), title)
+// addSpecification adds the specification as a standalone and a
+// printable page, styled with the tokens and fonts of the website.
+// Returns a paragraph linking both.
+func (me *Website) addSpecification(t tokens.Tokens) (*Element, error) {
+	faces := NewCSS()
+	addFontFaces(faces, me.fonts)
+	page := spec.NewSpecificationPage(t, faces)
+	page.Filename = "spec.html"
+	print := spec.NewSpecificationPage(t, faces)
+	print.Filename = "spec_print.html"
+	// images are read from staticDir
+	if err := spec.Inline(print.Element, staticDir, t); err != nil {
+		return nil, err
+	}
+	me.add(page)
+	me.add(print)
+	return P("The specification is also available as a ",
+		A(Href(page.Filename), "standalone page"), " and ready for ",
+		A(Href(print.Filename), "printing"), ".",
+	), nil
 }
 
-func (me *Website) AddDrill(right, args string, filename string) *Element {
-	article := Article(
-		loadExample(filename),
-		example(args, filename),
-	)
-	page := NewFile(filepath.Base(toHtmlFile(filename)),
-		Html(Lang("en"),
-			Head(
-				Meta(Charset("utf-8")),
-				Meta(
-					Name("viewport"),
-					Content("width=device-width, initial-scale=1.0"),
-				),
-				stylesheet("../theme.css"),
-				stylesheet("../a4.css"),
-				Title(right, " - drill"),
-			),
-			Body(
-				Header(
-					Header(Code(
-						right,
-						" - ",
-						A(Href("../index.html"), me.title).String(),
-					)),
-				),
-				article,
-				Footer(me.author),
-			),
-		),
-	)
-	me.drills = append(me.drills, page)
-	return linkDrill(filename)
+// runLink returns a link to the given program in the playground.
+func (me *Website) runLink(src []byte) *Element {
+	return A(Href(me.playground.Link(src)), "Run this")
 }
 
 func (me *Website) AddThemes(v ...*CSS) {
 	me.themes = append(me.themes, v...)
 }
 
+// addTheme adds the theme unless one with the same filename exists.
+func (me *Website) addTheme(v *CSS) {
+	for _, theme := range me.themes {
+		if theme.Filename == v.Filename {
+			return
+		}
+	}
+	me.themes = append(me.themes, v)
+}
+
 func (me *Website) add(p *Page) {
 	me.pages = append(me.pages, p)
 }
@@ -249,18 +367,38 @@ type saveAll struct {
 }
 
 func (me *saveAll) SaveTo(base string) error {
+	if me.err != nil {
+		return me.err
+	}
+	if err := me.resolveRefs(); err != nil {
+		return err
+	}
+	if err := checkLinks(me.pages); err != nil {
+		return err
+	}
+	if err := checkAudit(me.Audit(), me.auditLevel); err != nil {
+		return err
+	}
+	if me.offline {
+		if err := checkOffline(me.pages, me.themes); err != nil {
+			return err
+		}
+	}
+	if err := me.validatePages(); err != nil {
+		return err
+	}
+	if err := me.saveAssets(base); err != nil {
+		return err
+	}
 	p := &savePagesOnly{me.Website}
 	if err := p.SaveTo(base); err != nil {
 		return err
 	}
-
-	for _, theme := range me.themes {
-		theme.SaveTo(base)
+	if err := me.saveFonts(base); err != nil {
+		return err
 	}
-	drills := filepath.Join(base, "drill")
-	os.MkdirAll(drills, 0755)
-	for _, drill := range me.drills {
-		if err := drill.SaveTo(drills); err != nil {
+	for _, b := range me.bundles {
+		if err := b.SaveTo(base); err != nil {
 			return err
 		}
 	}
@@ -273,6 +411,10 @@ type savePagesOnly struct {
 
 func (me *savePagesOnly) SaveTo(base string) error {
 	for _, page := range me.pages {
+		dir := filepath.Join(base, filepath.Dir(page.Filename))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
 		if err := page.SaveTo(base); err != nil {
 			return err
 		}
@@ -0,0 +1,31 @@
+package website
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+	"github.com/sogvin/website/tokens"
+)
+
+func TestWebsite_addSpecification(t *testing.T) {
+	site := &Website{fonts: []*fontFace{parseFontName("Inconsolata")}}
+	links, err := site.addSpecification(tokens.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := asserter.New(t)
+	var hrefs, filenames []string
+	for _, a := range Query(links, "a") {
+		hrefs = append(hrefs, a.AttrVal("href"))
+	}
+	for _, page := range site.pages {
+		filenames = append(filenames, page.Filename)
+	}
+	assert().Equals(hrefs, []string{"spec.html", "spec_print.html"})
+	assert().Equals(filenames, hrefs)
+	for _, page := range site.pages {
+		assert().Contains(page.String(), "font-family: 'Inconsolata'")
+	}
+	assert().Contains(site.pages[1].String(), "page-break-inside: avoid")
+}
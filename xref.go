@@ -0,0 +1,118 @@
+package website
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	. "github.com/gregoryv/web"
+)
+
+// ref returns a link to the target registered under key, resolved
+// when the website is saved. Without label the link text is the title
+// of the target, e.g. "Figure 2".
+//
+// Pages are registered with keys derived from their title, e.g.
+// "nexus-pattern", sections as "nexus-pattern/summary" and drills as
+// "drill/flag-types". Figures and listings are numbered per page and
+// registered under the page, e.g. "nexus-pattern/chart", within the
+// same page the key given to figure or listing is enough.
+func ref(key string, label ...interface{}) *Element {
+	return A(Href(refScheme + key)).With(label...)
+}
+
+const refScheme = "ref:"
+
+// refKey returns a lower case key of words in title separated by
+// dashes.
+func refKey(title string) string {
+	return strings.ReplaceAll(filenameFrom(title), "_", "-")
+}
+
+// reference is a registered target of ref links.
+type reference struct {
+	title string
+	href  string // relative to website base
+}
+
+// relativeTo returns the href of the reference relative to the given
+// file.
+func (me *reference) relativeTo(filename string) string {
+	file, frag, _ := strings.Cut(me.href, "#")
+	if frag != "" {
+		frag = "#" + frag
+	}
+	if file == filename {
+		return frag
+	}
+	rel, _ := filepath.Rel(filepath.Dir(filename), file)
+	return filepath.ToSlash(rel) + frag
+}
+
+// register adds a reference target, panics on duplicate keys.
+func (me *Website) register(key, title, href string) {
+	if _, found := me.refs[key]; found {
+		panic(fmt.Sprintf("duplicate reference %q", key))
+	}
+	me.refs[key] = &reference{title: title, href: href}
+}
+
+// registerPage registers the page, its anchored sections and numbers
+// figures and listings in it, see number.
+func (me *Website) registerPage(title, filename string, article *Element) {
+	key := refKey(title)
+	me.register(key, title, filename)
+	me.number(key, title, filename, article, "figure", "Figure")
+	me.number(key, title, filename, article, "listing", "Listing")
+	for _, h := range queryHeadings(article, tocHeadings...) {
+		text := headingText(h)
+		skey := key + "/" + refKey(text)
+		if _, found := me.refs[skey]; found {
+			continue // repeated headings or figure, first one wins
+		}
+		me.register(skey, text, filename+"#"+h.AttrVal("id"))
+	}
+}
+
+// resolveRefs sets href and, if missing, text of all ref links.
+func (me *Website) resolveRefs() error {
+	for _, page := range me.pages {
+		if err := me.resolve(page, page.Filename); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve ref links in page, filename is relative to website base.
+func (me *Website) resolve(page *Page, filename string) error {
+	for _, a := range Query(page.Element, "a") {
+		href := a.Attr("href")
+		if href == nil || !strings.HasPrefix(href.Val, refScheme) {
+			continue
+		}
+		key := strings.TrimPrefix(href.Val, refScheme)
+		r, found := me.refs[key]
+		if !found {
+			r, found = me.refs[me.pageKey(filename)+"/"+key]
+		}
+		if !found {
+			return fmt.Errorf("%s: unknown reference %q", filename, key)
+		}
+		href.Val = r.relativeTo(filename)
+		if len(a.Children) == 0 {
+			a.With(r.title)
+		}
+	}
+	return nil
+}
+
+// pageKey returns the key of the page registered with filename.
+func (me *Website) pageKey(filename string) string {
+	for key, r := range me.refs {
+		if r.href == filename {
+			return key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package website
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	. "github.com/gregoryv/web"
+)
+
+func Test_resolveRefs(t *testing.T) {
+	site := &Website{refs: make(map[string]*reference)}
+	article := Article(
+		H1("Nexus pattern"),
+		P(ref("nexus-pattern/summary"), ref("chart")),
+		figure("chart", "A chart", Img(Src("chart.png"))),
+		H2("Summary"),
+	)
+	anchorHeadings(article)
+	site.registerPage("Nexus pattern", "nexus_pattern.html", article)
+	site.add(NewFile("nexus_pattern.html", article))
+	site.add(NewFile("drill/logging.html", P(ref("nexus-pattern", "see nexus"))))
+	ok, bad := asserter.NewErrors(t)
+	ok(site.resolveRefs())
+	assert := asserter.New(t)
+	assert().Equals(links(site.pages[0].Element), [][]string{
+		{"#summary", "Summary"}, {"#chart", "Figure 1"},
+	})
+	assert().Equals(links(site.pages[1].Element), [][]string{
+		{"../nexus_pattern.html", "see nexus"},
+	})
+
+	other := Article(
+		H1("Other"),
+		P(ref("chart"), ref("nexus-pattern/chart")),
+		figure("chart", "Another chart"),
+	)
+	site.registerPage("Other", "other.html", other)
+	site.pages = []*Page{NewFile("other.html", other)}
+	ok(site.resolveRefs())
+	assert().Equals(links(site.pages[0].Element), [][]string{
+		{"#chart", "Figure 1"}, {"nexus_pattern.html#chart", "Figure 1"},
+	})
+
+	site.add(NewFile("x.html", ref("no-such-key")))
+	bad(site.resolveRefs())
+}
+
+func Test_number(t *testing.T) {
+	site := &Website{refs: make(map[string]*reference)}
+	article := Article(
+		H1("Diagrams"),
+		figure("a", "First", Img(Src("a.png"))),
+		listing("b", "Code"),
+		figure("c", `Second
+		one`),
+	)
+	site.registerPage("Diagrams", "diagrams.html", article)
+
+	assert := asserter.New(t)
+	var numbers []string
+	for _, span := range Query(article, "span.number") {
+		numbers = append(numbers, span.Text())
+	}
+	assert().Equals(numbers, []string{"Figure 1.", "Listing 1.", "Figure 2."})
+	assert().Equals(MustQueryOne(article, "img").AttrVal("alt"), "First")
+	assert().Equals(len(site.figures), 2)
+	assert().Equals(site.figures[1].caption, "Second one")
+	assert().Equals(site.refs["diagrams/c"].href, "diagrams.html#c")
+}
+
+func Test_resolveRefs_sections(t *testing.T) {
+	site := &Website{refs: make(map[string]*reference)}
+	article := Article(
+		H1("A"),
+		H2("Setup", Id("preset")),
+		H3("Setup"),
+		H2("Run"),
+	)
+	anchorHeadings(article)
+	site.registerPage("A", "a.html", article)
+	b := NewFile("b.html", Article(ref("a/setup"), ref("a/run")))
+	site.add(b)
+	ok, bad := asserter.NewErrors(t)
+	ok(site.resolveRefs())
+	assert := asserter.New(t)
+	assert().Equals(links(b.Element), [][]string{
+		{"a.html#preset", "Setup"}, {"a.html#run", "Run"},
+	})
+	site.add(NewFile("c.html", ref("renamed/setup")))
+	bad(site.resolveRefs()) // renamed page
+}
+
+// links returns href and text of links in root, except heading self
+// links.
+func links(root *Element) [][]string {
+	res := make([][]string, 0)
+	for _, a := range Query(root, "a") {
+		if a.AttrVal("class") == "self" {
+			continue
+		}
+		res = append(res, []string{a.AttrVal("href"), a.Text()})
+	}
+	return res
+}